@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -14,7 +15,10 @@ import (
 
 const (
 	keepAliveInterval = 5 * time.Minute
+	requestTimeout    = 30 * time.Second
 	sessionCookieName = "PHPSESSID"
+	csrfCookieName    = "X-CSRF-TOKEN"
+	csrfHeaderName    = "X-CSRF-TOKEN"
 )
 
 var (
@@ -27,7 +31,9 @@ type Client struct {
 	Password  string
 	TLSConfig *tls.Config
 
+	authMu    sync.Mutex
 	sessionID string
+	authTime  time.Time
 
 	http struct {
 		http.Client
@@ -46,6 +52,7 @@ func (c *Client) ensureInit() (err error) {
 	c.http.Client.Transport = &http.Transport{
 		TLSClientConfig: c.TLSConfig,
 	}
+	c.http.Client.Timeout = requestTimeout
 
 	c.http.URL, err = url.Parse(c.Host)
 	if err != nil {
@@ -72,6 +79,40 @@ func (c *Client) Login() error {
 		return err
 	}
 
+	if err := c.authenticate(); err != nil {
+		return err
+	}
+
+	c.keepAlive()
+
+	return nil
+}
+
+// authenticate starts a new session.
+func (c *Client) authenticate() error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	return c.authenticateLocked()
+}
+
+// reauthenticate starts a new session, unless one was started in the last
+// keepAliveInterval. Sessions are kept alive, so a request denied that soon
+// is not denied because the session expired, and logging in again would
+// not help.
+func (c *Client) reauthenticate() (bool, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if time.Since(c.authTime) < keepAliveInterval {
+		return false, nil
+	}
+
+	return true, c.authenticateLocked()
+}
+
+// authenticateLocked must be called with authMu held.
+func (c *Client) authenticateLocked() error {
 	// Credentials
 	creds := make(url.Values, 2)
 	creds.Set("username", c.Username)
@@ -94,7 +135,7 @@ func (c *Client) Login() error {
 		return ErrAuthenticationFailed
 	}
 
-	c.keepAlive()
+	c.authTime = time.Now()
 
 	return nil
 }
@@ -155,7 +196,11 @@ func (c *Client) Subscribe(topics ...string) (*Subscription, error) {
 		return nil, err
 	}
 
-	return newSubscription(conn, c.sessionID, topics...)
+	c.authMu.Lock()
+	sessionID := c.sessionID
+	c.authMu.Unlock()
+
+	return newSubscription(conn, sessionID, topics...)
 }
 
 func (c *Client) Close() error {
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// InterfaceConfig is the configuration of an interface.
+type InterfaceConfig struct {
+	Name        string
+	Type        string
+	VLAN        string
+	Parent      string
+	Description string
+}
+
+type interfaceNode struct {
+	Description string                   `json:"description"`
+	VIF         map[string]interfaceNode `json:"vif"`
+	PPPoE       map[string]interfaceNode `json:"pppoe"`
+}
+
+// walk flattens the interface tree, as in "eth0" > vif "100" > pppoe "0"
+func (n interfaceNode) walk(name, t, vlan, parent string, dst map[string]*InterfaceConfig) {
+	dst[name] = &InterfaceConfig{
+		Name:        name,
+		Type:        t,
+		VLAN:        vlan,
+		Parent:      parent,
+		Description: n.Description,
+	}
+
+	for id, vif := range n.VIF {
+		vif.walk(name+"."+id, "vlan", id, name, dst)
+	}
+
+	for id, pppoe := range n.PPPoE {
+		pppoe.walk("pppoe"+id, "pppoe", "", name, dst)
+	}
+}
+
+// Interfaces returns the configuration of every interface, keyed by name.
+// Sections that do not follow the usual layout are skipped.
+func (c *Client) Interfaces() (map[string]*InterfaceConfig, error) {
+	raw, err := c.GetConfig("interfaces")
+	if err != nil {
+		return nil, err
+	}
+
+	sections := make(map[string]json.RawMessage)
+	if raw != nil {
+		if err := json.Unmarshal(raw, &sections); err != nil {
+			return nil, err
+		}
+	}
+
+	ret := make(map[string]*InterfaceConfig)
+	for t, raw := range sections {
+		ifaces := make(map[string]interfaceNode)
+		if err := json.Unmarshal(raw, &ifaces); err != nil {
+			continue
+		}
+
+		for name, n := range ifaces {
+			n.walk(name, t, "", "", ret)
+		}
+	}
+
+	return ret, nil
+}
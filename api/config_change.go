@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+func init() {
+	RegisterTopic(TopicConfigChange, func(data json.RawMessage) ([]Message, error) {
+		r := &ConfigChange{}
+		if err := json.Unmarshal(data, r); err != nil {
+			return nil, err
+		}
+
+		return []Message{r}, nil
+	})
+}
+
+// ConfigChange is a configuration change event, as reported by the
+// "config-change" topic.
+type ConfigChange struct {
+	// Subtrees holds the reported value for each changed config subtree.
+	Subtrees map[string]string
+}
+
+func (s *ConfigChange) Topic() string {
+	return TopicConfigChange
+}
+
+func (s *ConfigChange) UnmarshalJSON(data []byte) error {
+	kv := make(map[string]interface{})
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return err
+	}
+
+	s.Subtrees = make(map[string]string, len(kv))
+	for k, v := range kv {
+		switch val := v.(type) {
+		case string:
+			s.Subtrees[k] = val
+		default:
+			b, _ := json.Marshal(val)
+			s.Subtrees[k] = string(b)
+		}
+	}
+
+	return nil
+}
+
+// String lists the changed subtrees, sorted. The values are left out, as
+// they may hold credentials.
+func (s *ConfigChange) String() string {
+	keys := make([]string, 0, len(s.Subtrees))
+	for k := range s.Subtrees {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return strings.Join(keys, " ")
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// The router reports lease expirations in this layout.
+const dhcpExpirationLayout = "2006/01/02 15:04:05"
+
+// DHCPLease is a lease handed out by the DHCP server.
+type DHCPLease struct {
+	Pool     string
+	IP       string
+	MAC      string
+	Hostname string
+
+	// Expiration is zero for static mappings, and when the router time zone
+	// is not known.
+	Expiration time.Time
+}
+
+// DHCPPoolStat is the address usage of a DHCP server pool.
+type DHCPPoolStat struct {
+	Pool      string
+	Size      uint64
+	Leased    uint64
+	Available uint64
+}
+
+// timeZone returns the time zone configured in the router, UTC when none
+// is, or nil when it is not known to this host.
+func (c *Client) timeZone() (*time.Location, error) {
+	raw, err := c.GetConfig("system", "time-zone")
+	if err != nil || raw == nil {
+		return time.UTC, err
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, nil
+	}
+
+	return loc, nil
+}
+
+// DHCPLeases returns the DHCP server leases of every pool.
+// Expirations are reported in the router time zone.
+func (c *Client) DHCPLeases() ([]*DHCPLease, error) {
+	loc, err := c.timeZone()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.GetData("dhcp_leases")
+	if err != nil {
+		return nil, err
+	}
+
+	resp := struct {
+		Leases map[string]map[string]struct {
+			MAC        string `json:"mac"`
+			Hostname   string `json:"client-hostname"`
+			Expiration string `json:"expiration"`
+		} `json:"dhcp-server-leases"`
+	}{}
+
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+
+	var ret []*DHCPLease
+	for pool, leases := range resp.Leases {
+		for ip, v := range leases {
+			l := &DHCPLease{
+				Pool:     pool,
+				IP:       ip,
+				MAC:      v.MAC,
+				Hostname: v.Hostname,
+			}
+
+			if v.Expiration != "" && loc != nil {
+				if l.Expiration, err = time.ParseInLocation(dhcpExpirationLayout, v.Expiration, loc); err != nil {
+					return nil, err
+				}
+			}
+
+			ret = append(ret, l)
+		}
+	}
+
+	return ret, nil
+}
+
+// DHCPStats returns the address usage of every DHCP server pool.
+func (c *Client) DHCPStats() ([]*DHCPPoolStat, error) {
+	raw, err := c.GetData("dhcp_stats")
+	if err != nil {
+		return nil, err
+	}
+
+	resp := struct {
+		Stats map[string]map[string]string `json:"dhcp_server_stats"`
+	}{}
+
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+
+	ret := make([]*DHCPPoolStat, 0, len(resp.Stats))
+	for pool, v := range resp.Stats {
+		st := &DHCPPoolStat{
+			Pool: pool,
+		}
+
+		st.Size, err = parseUint(v["pool_size"])
+
+		if err == nil {
+			st.Leased, err = parseUint(v["leased"])
+		}
+
+		if err == nil {
+			st.Available, err = parseUint(v["available"])
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, st)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+func init() {
+	RegisterTopic(TopicDiscover, func(data json.RawMessage) ([]Message, error) {
+		r := discoverStatResp{}
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, err
+		}
+
+		ret := make([]Message, 0, len(r))
+		for _, stat := range r {
+			ret = append(ret, stat)
+		}
+
+		return ret, nil
+	})
+}
+
+// DiscoverStat is a neighbor found by UBNT discovery, as reported by the
+// "discover" topic.
+type DiscoverStat struct {
+	MAC      string
+	IP       string
+	Hostname string
+	Model    string
+	Firmware string
+	Uptime   uint64
+}
+
+func (s *DiscoverStat) Topic() string {
+	return TopicDiscover
+}
+
+type discoverStatResp []*DiscoverStat
+
+func (s *discoverStatResp) UnmarshalJSON(data []byte) error {
+	resp := struct {
+		Devices []struct {
+			HWAddr    string `json:"hwaddr"`
+			IPv4      string `json:"ipv4"`
+			Addresses []struct {
+				HWAddr string `json:"hwaddr"`
+				IPv4   string `json:"ipv4"`
+			} `json:"addresses"`
+			Hostname  string `json:"hostname"`
+			Product   string `json:"product"`
+			FWVersion string `json:"fwversion"`
+			Uptime    string `json:"uptime"`
+		} `json:"devices"`
+	}{}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+
+	for _, d := range resp.Devices {
+		st := &DiscoverStat{
+			MAC:      d.HWAddr,
+			IP:       d.IPv4,
+			Hostname: d.Hostname,
+			Model:    d.Product,
+			Firmware: d.FWVersion,
+		}
+
+		// Older firmwares only report the addresses list
+		if len(d.Addresses) > 0 {
+			if st.MAC == "" {
+				st.MAC = d.Addresses[0].HWAddr
+			}
+
+			if st.IP == "" {
+				st.IP = d.Addresses[0].IPv4
+			}
+		}
+
+		var err error
+		st.Uptime, err = parseUint(d.Uptime)
+		if err != nil {
+			return err
+		}
+
+		*s = append(*s, st)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterTopic(TopicExport, func(data json.RawMessage) ([]Message, error) {
+		r := exportStatResp{}
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, err
+		}
+
+		ret := make([]Message, 0, len(r))
+		for _, stat := range r {
+			ret = append(ret, stat)
+		}
+
+		return ret, nil
+	})
+}
+
+// ExportStat is the DPI traffic accounted to a single client for a single
+// application, as reported by the "export" topic.
+type ExportStat struct {
+	Client      string
+	Category    string
+	Application string
+
+	RxBytes uint64
+	TxBytes uint64
+	RxRate  uint64
+	TxRate  uint64
+}
+
+func (s *ExportStat) Topic() string {
+	return TopicExport
+}
+
+type exportStatResp []*ExportStat
+
+// The "export" topic is keyed by client IP, then by "<category>|<application>".
+func (s *exportStatResp) UnmarshalJSON(data []byte) error {
+	kv := make(map[string]map[string]map[string]string)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return err
+	}
+
+	for client, apps := range kv {
+		for k, v := range apps {
+			st := &ExportStat{
+				Client: client,
+			}
+
+			st.Category, st.Application = splitDPIKey(k)
+
+			var err error
+			st.RxBytes, err = parseUint(v["rx_bytes"])
+
+			if err == nil {
+				st.TxBytes, err = parseUint(v["tx_bytes"])
+			}
+
+			if err == nil {
+				st.RxRate, err = parseUint(v["rx_rate"])
+			}
+
+			if err == nil {
+				st.TxRate, err = parseUint(v["tx_rate"])
+			}
+
+			if err != nil {
+				return err
+			}
+
+			*s = append(*s, st)
+		}
+	}
+
+	return nil
+}
+
+func splitDPIKey(k string) (category, application string) {
+	i := strings.Index(k, "|")
+	if i < 0 {
+		return "", k
+	}
+
+	return k[:i], k[i+1:]
+}
+
+// parseUint treats missing values as zero.
+func parseUint(v string) (uint64, error) {
+	if v == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(v, 10, 64)
+}
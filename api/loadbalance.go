@@ -0,0 +1,236 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+)
+
+// DefaultGateway is the gateway of the default route in use.
+type DefaultGateway struct {
+	Gateway   string
+	Interface string
+}
+
+// LoadBalanceInterface is the health of an interface in a load-balance
+// group, as in "show load-balance status" and "show load-balance watchdog".
+type LoadBalanceInterface struct {
+	Group     string
+	Interface string
+
+	// Status is "active", "failover" or "inactive".
+	Status  string
+	Carrier bool
+	Gateway string
+
+	// PingOK is whether the watchdog considers the interface healthy.
+	PingOK     bool
+	Pings      uint64
+	Fails      uint64
+	RouteDrops uint64
+}
+
+// DefaultGateway returns the default gateway in use. The gateway is empty
+// when there is no default route.
+func (c *Client) DefaultGateway() (*DefaultGateway, error) {
+	raw, err := c.GetData("default_gateway")
+	if err != nil {
+		return nil, err
+	}
+
+	resp := struct {
+		Gateway   string `json:"gateway"`
+		Interface string `json:"interface"`
+	}{}
+
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+
+	return &DefaultGateway{
+		Gateway:   resp.Gateway,
+		Interface: resp.Interface,
+	}, nil
+}
+
+type loadBalanceKey struct {
+	group string
+	iface string
+}
+
+// lineField splits a "key : value" line.
+func lineField(line string) (key, value string, ok bool) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return "", "", false
+	}
+
+	return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:]), true
+}
+
+// parseLoadBalanceStatus parses the output of "show load-balance status",
+// as in
+//
+//	Group WAN_FAILOVER
+//	    interface   : eth0
+//	    carrier     : up
+//	    status      : active
+//	    gateway     : 203.0.113.1
+//	    route table : 201
+//	    weight      : 100%
+func parseLoadBalanceStatus(out string, dst map[loadBalanceKey]*LoadBalanceInterface) []*LoadBalanceInterface {
+	var ret []*LoadBalanceInterface
+	var group string
+	var cur *LoadBalanceInterface
+
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+
+		if strings.HasPrefix(line, "Group ") {
+			group = strings.TrimSpace(strings.TrimPrefix(line, "Group "))
+			cur = nil
+			continue
+		}
+
+		k, v, ok := lineField(line)
+		if !ok || group == "" {
+			continue
+		}
+
+		switch k {
+		case "interface":
+			cur = &LoadBalanceInterface{Group: group, Interface: v}
+			dst[loadBalanceKey{group, v}] = cur
+			ret = append(ret, cur)
+		case "carrier":
+			if cur != nil {
+				cur.Carrier = v == "up"
+			}
+		case "status":
+			if cur != nil {
+				cur.Status = v
+			}
+		case "gateway":
+			if cur != nil {
+				cur.Gateway = v
+			}
+		}
+	}
+
+	return ret
+}
+
+// parseLoadBalanceWatchdog adds the output of "show load-balance watchdog"
+// to the interfaces in dst, as in
+//
+//	Group WAN_FAILOVER
+//	  eth0
+//	  status: OK
+//	  pings: 1234
+//	  fails: 2
+//	  run fails: 0/3
+//	  route drops: 1
+//	  ping gateway: ping.ubnt.com - REACHABLE
+func parseLoadBalanceWatchdog(out string, dst map[loadBalanceKey]*LoadBalanceInterface) error {
+	var group string
+	var cur *LoadBalanceInterface
+
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+
+		if strings.HasPrefix(line, "Group ") {
+			group = strings.TrimSpace(strings.TrimPrefix(line, "Group "))
+			cur = nil
+			continue
+		}
+
+		if group == "" || line == "" {
+			continue
+		}
+
+		k, v, ok := lineField(line)
+		if !ok {
+			// Interface names and notes, as in "failover-only mode"
+			if st, ok := dst[loadBalanceKey{group, line}]; ok {
+				cur = st
+			}
+
+			continue
+		}
+
+		if cur == nil {
+			continue
+		}
+
+		var err error
+		switch k {
+		case "status":
+			cur.PingOK = v == "OK" || v == "Running"
+		case "pings":
+			cur.Pings, err = parseUint(v)
+		case "fails":
+			cur.Fails, err = parseUint(v)
+		case "route drops":
+			cur.RouteDrops, err = parseUint(v)
+		case "ping gateway":
+			cur.PingOK = cur.PingOK && strings.HasSuffix(v, " - REACHABLE")
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// operationText decodes the output of an operation, which is the text the
+// command prints.
+func (c *Client) operationText(name string) (string, error) {
+	raw, err := c.Operation(name)
+	if err != nil {
+		return "", err
+	}
+
+	var ret string
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &ret); err != nil {
+			return "", err
+		}
+	}
+
+	return ret, nil
+}
+
+// LoadBalanceStatus returns the health of every interface of every
+// load-balance group, from the load-balance status and watchdog operations.
+// Their output is the text of the CLI commands, as shown in Ubiquiti's
+// "EdgeRouter - WAN Load-Balancing" article.
+// It is empty when no group is configured.
+func (c *Client) LoadBalanceStatus() ([]*LoadBalanceInterface, error) {
+	raw, err := c.GetConfig("load-balance")
+	if err != nil || raw == nil {
+		return nil, err
+	}
+
+	status, err := c.operationText("load-balance-status")
+	if err != nil {
+		return nil, err
+	}
+
+	watchdog, err := c.operationText("load-balance-watchdog")
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[loadBalanceKey]*LoadBalanceInterface)
+	ret := parseLoadBalanceStatus(status, byKey)
+
+	if err := parseLoadBalanceWatchdog(watchdog, byKey); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
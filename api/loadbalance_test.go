@@ -0,0 +1,91 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+const loadBalanceStatusOutput = `Group WAN_FAILOVER
+    interface   : eth0
+    carrier     : up
+    status      : active
+    gateway     : 203.0.113.1
+    route table : 201
+    weight      : 100%
+    fo_priority : 60
+    flows
+        WAN Out   : 1234
+        WAN In    : 0
+        Local ICMP: 5
+        Local DNS : 0
+        Local Data: 0
+
+    interface   : eth1
+    carrier     : down
+    status      : failover
+    gateway     : unknown
+    route table : 202
+    weight      : 0%
+    fo_priority : 60
+`
+
+const loadBalanceWatchdogOutput = `Group WAN_FAILOVER
+  eth0
+  status: OK
+  pings: 1234
+  fails: 2
+  run fails: 0/3
+  route drops: 1
+  ping gateway: ping.ubnt.com - REACHABLE
+
+  eth1
+  failover-only mode
+  status: Waiting on recovery (0/3)
+  pings: 1230
+  fails: 40
+  run fails: 3/3
+  route drops: 2
+  ping gateway: ping.ubnt.com - UNREACHABLE
+`
+
+func TestParseLoadBalance(t *testing.T) {
+	byKey := make(map[loadBalanceKey]*LoadBalanceInterface)
+	got := parseLoadBalanceStatus(loadBalanceStatusOutput, byKey)
+
+	if err := parseLoadBalanceWatchdog(loadBalanceWatchdogOutput, byKey); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*LoadBalanceInterface{
+		{
+			Group:      "WAN_FAILOVER",
+			Interface:  "eth0",
+			Status:     "active",
+			Carrier:    true,
+			Gateway:    "203.0.113.1",
+			PingOK:     true,
+			Pings:      1234,
+			Fails:      2,
+			RouteDrops: 1,
+		},
+		{
+			Group:      "WAN_FAILOVER",
+			Interface:  "eth1",
+			Status:     "failover",
+			Gateway:    "unknown",
+			Pings:      1230,
+			Fails:      40,
+			RouteDrops: 2,
+		},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d interfaces, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("got %+v, want %+v", *got[i], *want[i])
+		}
+	}
+}
@@ -5,6 +5,31 @@ import (
 	"strconv"
 )
 
+func init() {
+	RegisterTopic(TopicInterfaces, func(data json.RawMessage) ([]Message, error) {
+		r := interfaceStatResp{}
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, err
+		}
+
+		ret := make([]Message, 0, len(r))
+		for _, stat := range r {
+			ret = append(ret, stat)
+		}
+
+		return ret, nil
+	})
+
+	RegisterTopic(TopicSystemStats, func(data json.RawMessage) ([]Message, error) {
+		r := &SystemStat{}
+		if err := json.Unmarshal(data, r); err != nil {
+			return nil, err
+		}
+
+		return []Message{r}, nil
+	})
+}
+
 type topic string
 
 func (t topic) MarshalJSON() ([]byte, error) {
@@ -25,6 +50,10 @@ type SystemStat struct {
 	Mem    int
 }
 
+func (s *SystemStat) Topic() string {
+	return TopicSystemStats
+}
+
 func (s *SystemStat) UnmarshalJSON(data []byte) error {
 	kv := make(map[string]string)
 	err := json.Unmarshal(data, &kv)
@@ -136,3 +165,7 @@ type InterfaceStat struct {
 	TxBytesPerSec uint64
 	Multicast     uint64
 }
+
+func (s *InterfaceStat) Topic() string {
+	return TopicInterfaces
+}
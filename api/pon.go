@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+func init() {
+	RegisterTopic(TopicPONStats, func(data json.RawMessage) ([]Message, error) {
+		r := ponStatResp{}
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, err
+		}
+
+		ret := make([]Message, 0, len(r))
+		for _, stat := range r {
+			ret = append(ret, stat)
+		}
+
+		return ret, nil
+	})
+}
+
+// PONStat is the optical state of a single ONU, as reported by the
+// "pon-stats" topic.
+type PONStat struct {
+	Serial string
+	Port   string
+	LinkUp bool
+
+	RxPower     float64 // dBm
+	TxPower     float64 // dBm
+	BiasCurrent float64 // mA
+	Temperature float64 // Celsius
+	Voltage     float64 // V
+}
+
+func (s *PONStat) Topic() string {
+	return TopicPONStats
+}
+
+type ponStatResp []*PONStat
+
+// The "pon-stats" topic is keyed by PON port, then by ONU serial number.
+func (s *ponStatResp) UnmarshalJSON(data []byte) error {
+	kv := make(map[string]map[string]map[string]string)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return err
+	}
+
+	for port, onus := range kv {
+		for serial, v := range onus {
+			st := &PONStat{
+				Serial: serial,
+				Port:   port,
+				LinkUp: v["link"] == "up" || v["link"] == "true",
+			}
+
+			var err error
+			st.RxPower, err = parseFloat(v["rx_power"])
+
+			if err == nil {
+				st.TxPower, err = parseFloat(v["tx_power"])
+			}
+
+			if err == nil {
+				st.BiasCurrent, err = parseFloat(v["bias_current"])
+			}
+
+			if err == nil {
+				st.Temperature, err = parseFloat(v["temperature"])
+			}
+
+			if err == nil {
+				st.Voltage, err = parseFloat(v["voltage"])
+			}
+
+			if err != nil {
+				return err
+			}
+
+			*s = append(*s, st)
+		}
+	}
+
+	return nil
+}
+
+// parseFloat treats missing values as zero.
+func parseFloat(v string) (float64, error) {
+	if v == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseFloat(v, 64)
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// flexBool decodes the booleans reported by the API as true, "1" or "true".
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `true`, `"1"`, `"true"`, `1`:
+		*b = true
+	default:
+		*b = false
+	}
+
+	return nil
+}
+
+// BatchRequest is a set of config operations applied at once.
+// Each operation is a config tree, as in
+// {"interfaces": {"ethernet": {"eth1": {"description": "LAN"}}}}
+type BatchRequest struct {
+	Set    map[string]interface{} `json:"SET,omitempty"`
+	Delete map[string]interface{} `json:"DELETE,omitempty"`
+	Get    map[string]interface{} `json:"GET,omitempty"`
+}
+
+// BatchResult is the result of a single batch operation.
+type BatchResult struct {
+	Success flexBool        `json:"success"`
+	Failure flexBool        `json:"failure"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	Set    *BatchResult    `json:"SET,omitempty"`
+	Delete *BatchResult    `json:"DELETE,omitempty"`
+	Commit *BatchResult    `json:"COMMIT,omitempty"`
+	Save   *BatchResult    `json:"SAVE,omitempty"`
+	Get    json.RawMessage `json:"GET,omitempty"`
+
+	Success flexBool `json:"success"`
+}
+
+func (c *Client) csrfToken() string {
+	for _, ck := range c.http.Client.Jar.Cookies(c.http.URL) {
+		if ck.Name == csrfCookieName {
+			return ck.Value
+		}
+	}
+
+	return ""
+}
+
+// do sends an API request with the session CSRF token, and logs in again if
+// the session has expired. It returns ErrAuthenticationFailed if the
+// credentials are rejected.
+func (c *Client) do(method, path string, query url.Values, body []byte, dst interface{}) error {
+	send := func() (*http.Response, error) {
+		u := c.http.URL.ResolveReference(&url.URL{
+			Path:     path,
+			RawQuery: query.Encode(),
+		})
+
+		var r io.Reader
+		if body != nil {
+			r = bytes.NewReader(body)
+		}
+
+		req, err := http.NewRequest(method, u.String(), r)
+		if err != nil {
+			return nil, err
+		}
+
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		req.Header.Set(csrfHeaderName, c.csrfToken())
+		return c.http.Do(req)
+	}
+
+	resp, err := send()
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		ok, err := c.reauthenticate()
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return fmt.Errorf("%s: unexpected status: %s", path, resp.Status)
+		}
+
+		if resp, err = send(); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status: %s", path, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+// GetConfig returns the config subtree at path, as in
+// GetConfig("interfaces", "ethernet"). It returns the whole config when
+// path is empty.
+func (c *Client) GetConfig(path ...string) (json.RawMessage, error) {
+	resp := struct {
+		Get json.RawMessage `json:"GET"`
+	}{}
+
+	if err := c.do(http.MethodGet, "/api/edge/get.json", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	ret := resp.Get
+	for _, p := range path {
+		tree := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(ret, &tree); err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if ret, ok = tree[p]; !ok {
+			return nil, nil
+		}
+	}
+
+	return ret, nil
+}
+
+// GetData returns the output of a data source, as in GetData("dhcp_leases").
+func (c *Client) GetData(name string) (json.RawMessage, error) {
+	resp := struct {
+		Success flexBool        `json:"success"`
+		Error   string          `json:"error"`
+		Output  json.RawMessage `json:"output"`
+	}{}
+
+	query := url.Values{"data": []string{name}}
+	if err := c.do(http.MethodGet, "/api/edge/data.json", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("data %s: %s", name, resp.Error)
+	}
+
+	return resp.Output, nil
+}
+
+// Operation runs an operational command, as in Operation("reboot"), and
+// returns its output.
+func (c *Client) Operation(name string) (json.RawMessage, error) {
+	resp := struct {
+		Success flexBool        `json:"success"`
+		Error   string          `json:"error"`
+		Output  json.RawMessage `json:"output"`
+	}{}
+
+	if err := c.do(http.MethodPost, "/api/edge/operation/"+url.PathEscape(name)+".json", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("operation %s: %s", name, resp.Error)
+	}
+
+	return resp.Output, nil
+}
+
+// Batch applies a set of config operations.
+func (c *Client) Batch(req *BatchRequest) (*BatchResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &BatchResponse{}
+	if err := c.do(http.MethodPost, "/api/edge/batch.json", nil, body, resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return resp, fmt.Errorf("batch failed")
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Route protocols, by the code that prefixes routes in "show ip route".
+var routeProtocols = map[byte]string{
+	'K': "kernel",
+	'C': "connected",
+	'S': "static",
+	'R': "rip",
+	'O': "ospf",
+	'I': "isis",
+	'B': "bgp",
+}
+
+func init() {
+	RegisterTopic(TopicNumRoutes, func(data json.RawMessage) ([]Message, error) {
+		r := &RouteCountStat{}
+		if err := json.Unmarshal(data, r); err != nil {
+			return nil, err
+		}
+
+		return []Message{r}, nil
+	})
+}
+
+// RouteCountStat is the size of the routing table per protocol, as reported
+// by the "num-routes" topic.
+type RouteCountStat struct {
+	Protocols map[string]uint64
+	Total     uint64
+}
+
+func (s *RouteCountStat) Topic() string {
+	return TopicNumRoutes
+}
+
+func (s *RouteCountStat) UnmarshalJSON(data []byte) error {
+	kv := make(map[string]string)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return err
+	}
+
+	s.Protocols = make(map[string]uint64, len(kv))
+
+	var sum uint64
+	for k, v := range kv {
+		n, err := parseUint(v)
+		if err != nil {
+			return err
+		}
+
+		if k == "total" {
+			s.Total = n
+			continue
+		}
+
+		s.Protocols[k] = n
+		sum += n
+	}
+
+	// Not every firmware reports the total
+	if _, ok := kv["total"]; !ok {
+		s.Total = sum
+	}
+
+	return nil
+}
+
+// Route is a next hop of a prefix in the routing table.
+// Only the routes installed in the forwarding table are returned.
+type Route struct {
+	Prefix    string
+	NextHop   string
+	Interface string
+	Protocol  string
+	Distance  uint64
+	Metric    uint64
+}
+
+// parseRouteType splits a route type, as in "S>*", into its protocol and
+// whether it is installed in the forwarding table.
+func parseRouteType(t string) (protocol string, fib bool) {
+	if t == "" {
+		return "", false
+	}
+
+	protocol, ok := routeProtocols[t[0]]
+	if !ok {
+		protocol = strings.ToLower(t[:1])
+	}
+
+	return protocol, strings.Contains(t, "*")
+}
+
+// parseRouteMetric splits a route metric, as in "[110/20]" or "1/0", into
+// its administrative distance and metric.
+func parseRouteMetric(m string) (distance, metric uint64, err error) {
+	m = strings.Trim(m, "[]")
+	if m == "" {
+		return 0, 0, nil
+	}
+
+	parts := strings.SplitN(m, "/", 2)
+	if distance, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+		return 0, 0, err
+	}
+
+	if len(parts) == 2 {
+		metric, err = parseUint(parts[1])
+	}
+
+	return distance, metric, err
+}
+
+// Routes returns the routing table, one entry per next hop.
+func (c *Client) Routes() ([]*Route, error) {
+	raw, err := c.GetData("routes")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp []struct {
+		Prefix   string `json:"pfx"`
+		NextHops []struct {
+			Type      string `json:"t"`
+			Metric    string `json:"metric"`
+			Via       string `json:"via"`
+			Interface string `json:"intf"`
+		} `json:"nh"`
+	}
+
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+
+	var ret []*Route
+	for _, r := range resp {
+		for _, nh := range r.NextHops {
+			protocol, fib := parseRouteType(nh.Type)
+			if !fib {
+				continue
+			}
+
+			distance, metric, err := parseRouteMetric(nh.Metric)
+			if err != nil {
+				return nil, err
+			}
+
+			ret = append(ret, &Route{
+				Prefix:    r.Prefix,
+				NextHop:   nh.Via,
+				Interface: nh.Interface,
+				Protocol:  protocol,
+				Distance:  distance,
+				Metric:    metric,
+			})
+		}
+	}
+
+	return ret, nil
+}
@@ -68,13 +68,15 @@ func (s *messageStream) WriteJSON(src interface{}) error {
 func (s *messageStream) Close() error {
 	// Send close message
 	err := s.c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-	if err != nil {
-		return err
-	}
 
 	// TODO: Should it give the websocket a close deadline and wait?
 
-	return s.c.Close()
+	// Close the connection even if it is already broken
+	if cerr := s.c.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
 }
 
 func (s *messageStream) fillBuffer() error {
@@ -141,40 +143,31 @@ func (s *messageStream) Bytes() ([]byte, error) {
 	return s.Bytes()
 }
 
-func (s *messageStream) receiveNext(C chan<- interface{}) error {
+func (s *messageStream) receiveNext(C chan<- Message, done <-chan interface{}) error {
 	resp := make(map[string]json.RawMessage)
 	if err := s.ReadJSON(&resp); err != nil {
 		return err
 	}
 
-	//TODO: Parse and dispatch
 	for k, v := range resp {
-		switch k {
-		case "interfaces":
-			r := interfaceStatResp{}
-			if err := json.Unmarshal(v, &r); err != nil {
-				return err
-			}
-
-			for _, stat := range r {
-				C <- stat
-			}
-		case "system-stats":
-			r := &SystemStat{}
-			if err := json.Unmarshal(v, r); err != nil {
-				return err
-			}
-
-			C <- r
-		default:
+		decode, ok := decoderFor(k)
+		if !ok {
 			// log.Printf("-> %s", v)
 			continue
-			// case "export":
-			// case "discover":
-			// case "pon-stats":
-			// case "num-routes":
-			// case "config-change":
-			// case "users":
+		}
+
+		// A bad payload should not end the subscription
+		msgs, err := decode(v)
+		if err != nil {
+			msgs = []Message{&DecodeError{topic: k, Err: err}}
+		}
+
+		for _, m := range msgs {
+			select {
+			case C <- m:
+			case <-done:
+				return nil
+			}
 		}
 	}
 
@@ -13,7 +13,7 @@ func topicsFor(topics []string) []topic {
 }
 
 type Subscription struct {
-	C   <-chan interface{}
+	C   <-chan Message
 	Err <-chan error
 
 	done   chan interface{}
@@ -32,7 +32,7 @@ func newSubscription(conn *websocket.Conn, sessionID string, topics ...string) (
 	}
 
 	done := make(chan interface{})
-	resC := make(chan interface{})
+	resC := make(chan Message)
 	errC := make(chan error, 1)
 
 	go func() {
@@ -42,7 +42,7 @@ func newSubscription(conn *websocket.Conn, sessionID string, topics ...string) (
 			case <-done:
 				return
 			default:
-				if err := stream.receiveNext(resC); err != nil {
+				if err := stream.receiveNext(resC, done); err != nil {
 					errC <- err
 					return
 				}
@@ -59,6 +59,10 @@ func newSubscription(conn *websocket.Conn, sessionID string, topics ...string) (
 	}, nil
 }
 
+// Stop unsubscribes and closes the websocket connection.
 func (s *Subscription) Stop() {
 	close(s.done)
+
+	// Unblocks any pending read
+	s.stream.Close()
 }
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// SystemInfo identifies a router and its firmware.
+type SystemInfo struct {
+	Model    string
+	Version  string
+	Build    string
+	Serial   string
+	Hostname string
+}
+
+// parseSoftwareVersion splits a firmware version string, as in
+// "EdgeRouter.ER-e300.v2.0.9-hotfix.1.5371034.200728.1128", into its board,
+// version ("v2.0.9-hotfix.1") and build ("5371034.200728.1128").
+func parseSoftwareVersion(s string) (board, version, build string) {
+	i := strings.Index(s, ".v")
+	if i < 0 {
+		return "", s, ""
+	}
+
+	if prefix := strings.SplitN(s[:i], ".", 2); len(prefix) == 2 {
+		board = prefix[1]
+	}
+
+	fields := strings.Split(s[i+1:], ".")
+	if len(fields) < 4 {
+		return board, s[i+1:], ""
+	}
+
+	n := len(fields) - 3
+	return board, strings.Join(fields[:n], "."), strings.Join(fields[n:], ".")
+}
+
+// SystemInfo returns the model, firmware and host name of the router.
+// The model is taken from the firmware version when not reported.
+func (c *Client) SystemInfo() (*SystemInfo, error) {
+	raw, err := c.GetData("sys_info")
+	if err != nil {
+		return nil, err
+	}
+
+	resp := struct {
+		SoftwareVersion string `json:"sw_ver"`
+		Model           string `json:"model"`
+		Serial          string `json:"serial"`
+	}{}
+
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+
+	board, version, build := parseSoftwareVersion(resp.SoftwareVersion)
+
+	ret := &SystemInfo{
+		Model:   resp.Model,
+		Version: version,
+		Build:   build,
+		Serial:  resp.Serial,
+	}
+
+	if ret.Model == "" {
+		ret.Model = board
+	}
+
+	raw, err = c.GetConfig("system", "host-name")
+	if err != nil {
+		return nil, err
+	}
+
+	if raw != nil {
+		if err := json.Unmarshal(raw, &ret.Hostname); err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
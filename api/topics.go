@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+const (
+	TopicInterfaces   = "interfaces"
+	TopicSystemStats  = "system-stats"
+	TopicExport       = "export"
+	TopicDiscover     = "discover"
+	TopicPONStats     = "pon-stats"
+	TopicNumRoutes    = "num-routes"
+	TopicConfigChange = "config-change"
+	TopicUsers        = "users"
+)
+
+// Message is a value decoded from a stats topic.
+type Message interface {
+	Topic() string
+}
+
+// DecodeError is sent in place of the messages of a topic payload that
+// could not be decoded.
+type DecodeError struct {
+	topic string
+	Err   error
+}
+
+func (e *DecodeError) Topic() string {
+	return e.topic
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.topic, e.Err)
+}
+
+// DecodeFunc decodes the payload of a topic into zero or more messages.
+type DecodeFunc func(json.RawMessage) ([]Message, error)
+
+var decoders = struct {
+	sync.RWMutex
+	m map[string]DecodeFunc
+}{
+	m: make(map[string]DecodeFunc),
+}
+
+// RegisterTopic makes a topic decoder available to every Subscription.
+// Payloads from topics without a decoder are ignored.
+// It panics if decode is nil or if a decoder is already registered for name.
+func RegisterTopic(name string, decode DecodeFunc) {
+	decoders.Lock()
+	defer decoders.Unlock()
+
+	if decode == nil {
+		panic("api: RegisterTopic decoder is nil")
+	}
+
+	if _, dup := decoders.m[name]; dup {
+		panic(fmt.Sprintf("api: RegisterTopic called twice for topic %q", name))
+	}
+
+	decoders.m[name] = decode
+}
+
+func decoderFor(name string) (DecodeFunc, bool) {
+	decoders.RLock()
+	defer decoders.RUnlock()
+
+	decode, ok := decoders.m[name]
+	return decode, ok
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+func init() {
+	RegisterTopic(TopicUsers, func(data json.RawMessage) ([]Message, error) {
+		r := &UsersStat{}
+		if err := json.Unmarshal(data, r); err != nil {
+			return nil, err
+		}
+
+		return []Message{r}, nil
+	})
+}
+
+// UserSession is an active login session on the router.
+type UserSession struct {
+	User     string
+	Address  string
+	Type     string
+	Duration uint64 // seconds
+}
+
+// UsersStat is the list of active sessions per login type, as reported by
+// the "users" topic.
+type UsersStat struct {
+	Sessions map[string][]UserSession
+}
+
+func (s *UsersStat) Topic() string {
+	return TopicUsers
+}
+
+// The "users" topic is keyed by login type, and each session is keyed by the
+// user name.
+func (s *UsersStat) UnmarshalJSON(data []byte) error {
+	kv := make(map[string][]map[string]struct {
+		Host     string `json:"host"`
+		Duration string `json:"duration"`
+	})
+
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return err
+	}
+
+	s.Sessions = make(map[string][]UserSession, len(kv))
+
+	for t, entries := range kv {
+		sessions := make([]UserSession, 0, len(entries))
+
+		for _, e := range entries {
+			for user, v := range e {
+				d, err := parseUint(v.Duration)
+				if err != nil {
+					return err
+				}
+
+				sessions = append(sessions, UserSession{
+					User:     user,
+					Address:  v.Host,
+					Type:     t,
+					Duration: d,
+				})
+			}
+		}
+
+		s.Sessions[t] = sessions
+	}
+
+	return nil
+}
@@ -4,58 +4,107 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/pem"
 	"flag"
-	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
-	"github.com/juniorz/edgemax-exporter/api"
-	"github.com/juniorz/edgemax-exporter/collector"
+	"github.com/juniorz/edgemax-exporter/config"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var (
+	configFile string
+
 	configHost     string
 	configUser     string
 	configPassword string
 
 	configTLSSkipVerify bool
 	configTLSCACertPath string
+
+	configDPIMaxSeries int
+
+	configReadyMaxAge time.Duration
+	configReadyStrict bool
+	configStaleTTL    time.Duration
+
+	configMonotonicCounters bool
+
+	configPollInterval  time.Duration
+	configDHCPLeaseInfo bool
+
+	configRouteAllowlist cidrList
+
+	configProbeAllowedHosts hostList
+	configProbeIdleTimeout  time.Duration
+
+	configBackoffInitial   time.Duration
+	configBackoffMax       time.Duration
+	configAuthFailureRetry time.Duration
 )
 
 func init() {
+	flag.StringVar(&configFile, "config.file", "", "Path to a YAML configuration file with the modules and targets to export.\nWhen set, -host is ignored, and -user, -password, -tls-skip-verify and -ca-cert only apply when no \"default\" module is configured.")
+
 	flag.StringVar(&configHost, "host", "https://192.168.0.1", "EdgeMAX host")
 	flag.StringVar(&configUser, "user", "", "Username")
 	flag.StringVar(&configPassword, "password", "", "Password")
 
 	flag.BoolVar(&configTLSSkipVerify, "tls-skip-verify", false, "Disable verification of TLS certificates.\nUsing this option is highly discouraged as it decreases the security.")
 	flag.StringVar(&configTLSCACertPath, "ca-cert", "", "Path on the local disk to a single PEM-encoded CA certificate to verify the server's SSL certificate.")
+
+	flag.IntVar(&configDPIMaxSeries, "dpi-max-series", 1000, "Maximum number of client/application pairs exported from DPI stats.\nNew pairs are dropped once it is reached, until the existing ones expire after -stale-ttl.")
+
+	flag.DurationVar(&configStaleTTL, "stale-ttl", 5*time.Minute, "How long to keep exporting stats a target no longer reports, such as a deleted interface.\nZero disables expiration.")
+
+	flag.BoolVar(&configMonotonicCounters, "monotonic-counters", false, "Accumulate interface counters across resets, such as after a router reboot,\ninstead of exporting them as reported.")
+
+	flag.DurationVar(&configPollInterval, "poll-interval", 30*time.Second, "How often to fetch the stats that are not published on the websocket, such as DHCP leases.")
+	flag.BoolVar(&configDHCPLeaseInfo, "dhcp-lease-info", false, "Export every DHCP lease, not only the pool usage.")
+
+	flag.Var(&configRouteAllowlist, "route-allowlist", "Comma-separated CIDRs, as in 10.0.0.0/8,192.168.0.0/16. Routes within them are exported from the routing table.")
+
+	flag.DurationVar(&configBackoffInitial, "backoff-initial", 5*time.Second, "Initial delay before reconnecting to a target. Doubles on every failed attempt.")
+	flag.DurationVar(&configBackoffMax, "backoff-max", 5*time.Minute, "Maximum delay before reconnecting to a target.")
+	flag.DurationVar(&configAuthFailureRetry, "auth-failure-retry", 30*time.Minute, "Delay before reconnecting to a target that rejected the credentials.")
+
+	flag.Var(&configProbeAllowedHosts, "probe-allowed-hosts", "Comma-separated host names and CIDRs, as in edge1.example.com,10.0.0.0/8, that may be probed besides the configured targets.")
+	flag.DurationVar(&configProbeIdleTimeout, "probe-idle-timeout", 10*time.Minute, "How long to keep the session to a probed target that is no longer probed.")
+
+	flag.DurationVar(&configReadyMaxAge, "readyz-max-age", time.Minute, "Maximum age of the last message received from a target for /readyz to report it as ready.")
+	flag.BoolVar(&configReadyStrict, "readyz-strict", false, "Report /readyz as not ready when any configured target is not ready, instead of only when none is.")
 }
 
-func buildRootCAs(caPath string) (*x509.CertPool, error) {
-	if caPath == "" {
-		return nil, nil
-	}
+// cidrList is a comma-separated list of networks.
+type cidrList []*net.IPNet
 
-	r, err := ioutil.ReadFile(caPath)
-	if err != nil {
-		return nil, err
+func (l *cidrList) String() string {
+	ret := make([]string, len(*l))
+	for i, n := range *l {
+		ret[i] = n.String()
 	}
 
-	block, _ := pem.Decode(r)
-	cert, err := x509.ParseCertificate(block.Bytes)
-	if err != nil {
-		return nil, err
+	return strings.Join(ret, ",")
+}
+
+func (l *cidrList) Set(v string) error {
+	for _, cidr := range strings.Split(v, ",") {
+		_, n, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return err
+		}
+
+		*l = append(*l, n)
 	}
 
-	pool := x509.NewCertPool()
-	pool.AddCert(cert)
-	return pool, nil
+	return nil
 }
 
 func buildTLSConfig(skipVerify bool, caPath string) (*tls.Config, error) {
@@ -63,7 +112,7 @@ func buildTLSConfig(skipVerify bool, caPath string) (*tls.Config, error) {
 	var rootCAs *x509.CertPool = nil
 
 	if !skipVerify {
-		rootCAs, err = buildRootCAs(caPath)
+		rootCAs, err = config.LoadCAFile(caPath)
 	}
 
 	return &tls.Config{
@@ -73,6 +122,10 @@ func buildTLSConfig(skipVerify bool, caPath string) (*tls.Config, error) {
 }
 
 func readConfigFromEnv() {
+	if file, ok := os.LookupEnv("EDGEMAX_CONFIG_FILE"); ok {
+		configFile = file
+	}
+
 	if host, ok := os.LookupEnv("EDGEMAX_HOST"); ok {
 		configHost = host
 	}
@@ -118,16 +171,11 @@ func buildHTTPServer(handler http.Handler) (*http.Server, <-chan struct{}) {
 	return srv, serverTerminated
 }
 
-func buildRegistryFor(c prometheus.Collector) prometheus.Gatherer {
+func buildRegistry() *prometheus.Registry {
 	// Since we are dealing with custom Collector implementations, it might
 	// be a good idea to try it out with a pedantic registry.
 	reg := prometheus.NewPedanticRegistry()
 
-	// Wrap with edgemax host
-	prometheus.WrapRegistererWith(
-		prometheus.Labels{"edgemax_host": configHost}, reg,
-	).MustRegister(c)
-
 	// Add the standard process and Go metrics to the custom registry.
 	reg.MustRegister(
 		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
@@ -137,14 +185,45 @@ func buildRegistryFor(c prometheus.Collector) prometheus.Gatherer {
 	return reg
 }
 
-func buildHandler(c *api.Client) http.Handler {
-	metricsHandler := promhttp.HandlerFor(buildRegistryFor(collector.New(c)), promhttp.HandlerOpts{})
+func reloadHandler(targets *targetSet) http.HandlerFunc {
+	return func(resp http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			resp.Header().Set("Allow", http.MethodPost)
+			http.Error(resp, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := targets.reload(); err != nil {
+			log.Printf("error: reload: %s", err)
+			http.Error(resp, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// reloadOnSIGHUP reloads the configuration on every SIGHUP.
+func reloadOnSIGHUP(targets *targetSet) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		for range hup {
+			if err := targets.reload(); err != nil {
+				log.Printf("error: reload: %s", err)
+			}
+		}
+	}()
+}
+
+func buildHandler(reg prometheus.Gatherer, targets *targetSet) http.Handler {
+	metricsHandler := promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
 
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", metricsHandler)
-	mux.HandleFunc("/healthz", func(resp http.ResponseWriter, req *http.Request) {
-		//TODO
-	})
+	mux.Handle("/probe", probeHandler(targets))
+	mux.Handle("/-/reload", reloadHandler(targets))
+	mux.HandleFunc("/healthz", healthzHandler)
+	mux.Handle("/readyz", readyzHandler(targets, configReadyMaxAge, configReadyStrict))
 
 	return mux
 }
@@ -152,7 +231,7 @@ func buildHandler(c *api.Client) http.Handler {
 func Execute() {
 	readConfigFromEnv()
 
-	tlsConfig, err := buildTLSConfig(configTLSSkipVerify, configTLSCACertPath)
+	c, err := loadConfig()
 	if err != nil {
 		log.Fatalf("error: %s", err)
 	}
@@ -160,13 +239,13 @@ func Execute() {
 	// TODO:
 	// 1. Debug mode
 
-	mux := buildHandler(&api.Client{
-		Host:     configHost,
-		Username: configUser,
-		Password: configPassword,
+	reg := buildRegistry()
+	targets := newTargetSet(reg, c)
+	reloadOnSIGHUP(targets)
+
+	go targets.evictIdleProbes(configProbeIdleTimeout, time.Minute)
 
-		TLSConfig: tlsConfig,
-	})
+	mux := buildHandler(reg, targets)
 
 	srv, done := buildHTTPServer(mux)
 
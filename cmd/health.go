@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/juniorz/edgemax-exporter/collector"
+)
+
+type targetStatus struct {
+	collector.Status
+
+	Host   string `json:"host"`
+	Module string `json:"module"`
+	Ready  bool   `json:"ready"`
+}
+
+type healthResponse struct {
+	Status  string          `json:"status"`
+	Targets []*targetStatus `json:"targets,omitempty"`
+}
+
+func writeJSON(resp http.ResponseWriter, code int, v interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+
+	if err := json.NewEncoder(resp).Encode(v); err != nil {
+		log.Printf("error: %s", err)
+	}
+}
+
+// healthzHandler reports the process is alive.
+func healthzHandler(resp http.ResponseWriter, req *http.Request) {
+	writeJSON(resp, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// readyzHandler reports the readiness of every configured target, ready when
+// it has a session up and received data in the last maxAge.
+// The process is ready while any target is, so one router being down does
+// not stop the others from being scraped, or only when all are with strict.
+func readyzHandler(targets *targetSet, maxAge time.Duration, strict bool) http.HandlerFunc {
+	return func(resp http.ResponseWriter, req *http.Request) {
+		ret := healthResponse{}
+
+		ready := 0
+		for _, t := range targets.configured() {
+			st := &targetStatus{
+				Status: t.collector.Status(),
+				Host:   t.host,
+				Module: t.module,
+			}
+
+			st.Ready = st.LoggedIn && st.Connected &&
+				time.Since(st.LastMessageTime()) <= maxAge
+
+			if st.Ready {
+				ready++
+			}
+
+			ret.Targets = append(ret.Targets, st)
+		}
+
+		switch {
+		case ready == len(ret.Targets):
+			ret.Status = "ready"
+		case ready > 0:
+			ret.Status = "degraded"
+		default:
+			ret.Status = "not ready"
+		}
+
+		code := http.StatusOK
+		if ret.Status == "not ready" || (strict && ret.Status != "ready") {
+			code = http.StatusServiceUnavailable
+		}
+
+		writeJSON(resp, code, ret)
+	}
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// hostList is a comma-separated list of host names and networks.
+type hostList struct {
+	hosts    map[string]bool
+	networks []*net.IPNet
+}
+
+func (l *hostList) String() string {
+	ret := make([]string, 0, len(l.hosts)+len(l.networks))
+	for h := range l.hosts {
+		ret = append(ret, h)
+	}
+
+	for _, n := range l.networks {
+		ret = append(ret, n.String())
+	}
+
+	return strings.Join(ret, ",")
+}
+
+func (l *hostList) Set(v string) error {
+	for _, h := range strings.Split(v, ",") {
+		h = strings.TrimSpace(h)
+
+		if strings.Contains(h, "/") {
+			_, n, err := net.ParseCIDR(h)
+			if err != nil {
+				return err
+			}
+
+			l.networks = append(l.networks, n)
+			continue
+		}
+
+		if l.hosts == nil {
+			l.hosts = make(map[string]bool)
+		}
+
+		l.hosts[strings.ToLower(h)] = true
+	}
+
+	return nil
+}
+
+// contains reports whether the host of a target URL is in the list.
+func (l *hostList) contains(target string) bool {
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if l.hosts[host] {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, n := range l.networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// evictIdleProbes stops the probed targets not probed in the last idle,
+// checking every interval.
+func (s *targetSet) evictIdleProbes(idle, interval time.Duration) {
+	for range time.Tick(interval) {
+		s.Lock()
+
+		for k, t := range s.targets {
+			if !t.configured && time.Since(t.lastProbe) > idle {
+				s.remove(k)
+			}
+		}
+
+		s.Unlock()
+	}
+}
+
+// probeHandler exports a single target, as in
+// /probe?target=192.168.1.1&module=default
+// Targets are created on the first probe and reused until they are not
+// probed for -probe-idle-timeout. Only the hosts of configured targets and
+// the ones in -probe-allowed-hosts may be probed, so the exporter does not
+// send credentials to arbitrary hosts.
+func probeHandler(set *targetSet) http.HandlerFunc {
+	return func(resp http.ResponseWriter, req *http.Request) {
+		params := req.URL.Query()
+
+		host := params.Get("target")
+		if host == "" {
+			http.Error(resp, "target parameter is missing", http.StatusBadRequest)
+			return
+		}
+
+		module := params.Get("module")
+
+		t, err := set.getOrCreate(host, module)
+		if err == errUnknownModule {
+			http.Error(resp, fmt.Sprintf("unknown module %q", module), http.StatusBadRequest)
+			return
+		}
+
+		if err == errProbeNotAllowed {
+			http.Error(resp, fmt.Sprintf("target %q is not allowed", host), http.StatusForbidden)
+			return
+		}
+
+		if err != nil {
+			http.Error(resp, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		reg := prometheus.NewRegistry()
+		t.registerer(reg).MustRegister(t.collector)
+
+		promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(resp, req)
+	}
+}
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/juniorz/edgemax-exporter/api"
+	"github.com/juniorz/edgemax-exporter/collector"
+	"github.com/juniorz/edgemax-exporter/config"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	errUnknownModule   = fmt.Errorf("unknown module")
+	errProbeNotAllowed = fmt.Errorf("probe not allowed")
+)
+
+// target is a router being exported, with its own session and subscription.
+type target struct {
+	host     string
+	module   string
+	settings config.Module
+
+	// Configured targets are exported on /metrics, probed ones are not.
+	configured bool
+
+	// lastProbe is used to stop the probed targets no longer probed.
+	lastProbe time.Time
+
+	client    *api.Client
+	collector collector.Collector
+}
+
+type targetKey struct {
+	host   string
+	module string
+}
+
+func newTarget(host, module string, m config.Module) (*target, error) {
+	tlsConfig, err := buildTLSConfig(m.TLS.InsecureSkipVerify, m.TLS.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &api.Client{
+		Host:     host,
+		Username: m.User,
+		Password: m.Password,
+
+		TLSConfig: tlsConfig,
+	}
+
+	return &target{
+		host:      host,
+		module:    module,
+		settings:  m,
+		client:    client,
+		collector: collector.New(client, collectorOptions(m)),
+	}, nil
+}
+
+// registerer wraps reg with the edgemax host
+func (t *target) registerer(reg prometheus.Registerer) prometheus.Registerer {
+	return prometheus.WrapRegistererWith(
+		prometheus.Labels{"edgemax_host": t.host}, reg,
+	)
+}
+
+func collectorOptions(m config.Module) collector.Options {
+	return collector.Options{
+		Topics:       m.Topics,
+		DPIMaxSeries: configDPIMaxSeries,
+		StaleTTL:     configStaleTTL,
+
+		MonotonicCounters: configMonotonicCounters,
+
+		PollInterval:  configPollInterval,
+		DHCPLeaseInfo: configDHCPLeaseInfo,
+
+		RouteAllowlist: configRouteAllowlist,
+
+		BackoffInitial:   configBackoffInitial,
+		BackoffMax:       configBackoffMax,
+		AuthFailureRetry: configAuthFailureRetry,
+
+		OnConfigChange: func(m *api.ConfigChange) {
+			log.Printf("Config changed: %s", m)
+		},
+	}
+}
+
+// moduleFromFlags is the module configured by flags and environment.
+func moduleFromFlags() config.Module {
+	return config.Module{
+		User:     configUser,
+		Password: configPassword,
+		TLS: config.TLSConfig{
+			InsecureSkipVerify: configTLSSkipVerify,
+			CAFile:             configTLSCACertPath,
+		},
+	}
+}
+
+// loadConfig reads the configuration file, if any. Flags and environment
+// provide the default module when the file does not, and the only target
+// when there is no file.
+func loadConfig() (*config.Config, error) {
+	c := &config.Config{
+		Targets: []config.Target{{
+			Host:   configHost,
+			Module: config.DefaultModule,
+		}},
+	}
+
+	if configFile != "" {
+		var err error
+		if c, err = config.Load(configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Modules == nil {
+		c.Modules = make(map[string]config.Module, 1)
+	}
+
+	if _, ok := c.Modules[config.DefaultModule]; !ok {
+		c.Modules[config.DefaultModule] = moduleFromFlags()
+	}
+
+	return c, nil
+}
+
+// targetSet holds the targets being exported.
+type targetSet struct {
+	sync.Mutex
+
+	// reg exports the configured targets
+	reg prometheus.Registerer
+
+	modules map[string]config.Module
+	targets map[targetKey]*target
+}
+
+func newTargetSet(reg prometheus.Registerer, c *config.Config) *targetSet {
+	s := &targetSet{
+		reg:     reg,
+		targets: make(map[targetKey]*target, len(c.Targets)),
+	}
+
+	s.apply(c)
+
+	return s
+}
+
+// reload re-reads the configuration and applies it.
+// The running targets are kept if the configuration is not valid.
+func (s *targetSet) reload() error {
+	c, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	s.apply(c)
+	log.Printf("Configuration reloaded.")
+
+	return nil
+}
+
+// apply starts, stops and restarts targets to match c. Targets whose
+// settings did not change keep running, and keep their stats.
+// Targets that can not be built are skipped, so a misconfigured router does
+// not prevent the others from being exported.
+func (s *targetSet) apply(c *config.Config) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.modules = c.Modules
+
+	configured := make(map[targetKey]bool, len(c.Targets))
+	for _, t := range c.Targets {
+		configured[targetKey{config.NormalizeHost(t.Host), moduleOrDefault(t.Module)}] = true
+	}
+
+	for k, t := range s.targets {
+		m, ok := s.modules[k.module]
+		if !ok || !reflect.DeepEqual(m, t.settings) {
+			s.remove(k)
+			continue
+		}
+
+		if t.configured && !configured[k] {
+			s.remove(k)
+		}
+	}
+
+	for k := range configured {
+		t, err := s.getOrCreateLocked(k)
+		if err != nil {
+			log.Printf("error: %s: %s", k.host, err)
+			continue
+		}
+
+		if t.configured {
+			continue
+		}
+
+		if err := t.registerer(s.reg).Register(t.collector); err != nil {
+			log.Printf("error: %s: %s", k.host, err)
+			continue
+		}
+
+		t.configured = true
+	}
+}
+
+func (s *targetSet) configured() []*target {
+	s.Lock()
+	defer s.Unlock()
+
+	ret := make([]*target, 0, len(s.targets))
+	for _, t := range s.targets {
+		if t.configured {
+			ret = append(ret, t)
+		}
+	}
+
+	return ret
+}
+
+// remove must be called with the lock held.
+func (s *targetSet) remove(k targetKey) {
+	t := s.targets[k]
+	delete(s.targets, k)
+
+	if t.configured {
+		t.registerer(s.reg).Unregister(t.collector)
+	}
+
+	t.collector.Stop()
+}
+
+// getOrCreate reuses the target for host and module, if any, so every probe
+// of the same router shares the same session and subscription.
+// New targets are only created for allowed hosts.
+func (s *targetSet) getOrCreate(host, module string) (*target, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	k := targetKey{config.NormalizeHost(host), moduleOrDefault(module)}
+
+	if _, ok := s.targets[k]; !ok && !s.probeAllowed(k.host) {
+		return nil, errProbeNotAllowed
+	}
+
+	t, err := s.getOrCreateLocked(k)
+	if err != nil {
+		return nil, err
+	}
+
+	t.lastProbe = time.Now()
+
+	return t, nil
+}
+
+// probeAllowed must be called with the lock held.
+func (s *targetSet) probeAllowed(host string) bool {
+	for k, t := range s.targets {
+		if t.configured && k.host == host {
+			return true
+		}
+	}
+
+	return configProbeAllowedHosts.contains(host)
+}
+
+func (s *targetSet) getOrCreateLocked(k targetKey) (*target, error) {
+	if t, ok := s.targets[k]; ok {
+		return t, nil
+	}
+
+	m, ok := s.modules[k.module]
+	if !ok {
+		return nil, errUnknownModule
+	}
+
+	t, err := newTarget(k.host, k.module, m)
+	if err != nil {
+		return nil, err
+	}
+
+	s.targets[k] = t
+
+	return t, nil
+}
+
+func moduleOrDefault(module string) string {
+	if module == "" {
+		return config.DefaultModule
+	}
+
+	return module
+}
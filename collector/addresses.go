@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juniorz/edgemax-exporter/api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	ifaceAddressInfoDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "interface", "address_info"),
+		"Interface IP address.", []string{
+			"interface", "address", "family", "prefix_len",
+		}, nil,
+	)
+
+	ifaceAddressChangesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "interface", "address_changes_total"),
+		"Interface IP address changes.", []string{"interface"}, nil,
+	)
+)
+
+// ifaceAddresses tracks the addresses of an interface across reconnections.
+// It is not expired, so addresses changed while the router or the interface
+// were down for longer than StaleTTL are still counted.
+type ifaceAddresses struct {
+	last    string
+	changes uint64
+}
+
+type ifaceAddress struct {
+	address   string
+	family    string
+	prefixLen string
+}
+
+func parseAddress(a string) (ifaceAddress, bool) {
+	ip, n, err := net.ParseCIDR(a)
+	if err != nil {
+		return ifaceAddress{}, false
+	}
+
+	family := "ipv6"
+	if ip.To4() != nil {
+		family = "ipv4"
+	}
+
+	ones, _ := n.Mask.Size()
+	return ifaceAddress{ip.String(), family, strconv.Itoa(ones)}, true
+}
+
+// trackAddresses must be called with the lock held.
+func (c *collector) trackAddresses(m *api.InterfaceStat) {
+	addrs := append([]string(nil), m.Addresses...)
+	sort.Strings(addrs)
+	current := strings.Join(addrs, ",")
+
+	t, ok := c.ifaceAddresses[m.Name]
+	if !ok {
+		c.ifaceAddresses[m.Name] = &ifaceAddresses{last: current}
+		return
+	}
+
+	if t.last != current {
+		log.Printf("%s: addresses changed from [%s] to [%s]", m.Name, t.last, current)
+		t.last = current
+		t.changes++
+	}
+}
+
+func (c *collector) collectAddressMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	for _, stat := range c.interfaceStat {
+		seen := make(map[ifaceAddress]bool, len(stat.Addresses))
+		for _, a := range stat.Addresses {
+			addr, ok := parseAddress(a)
+			if !ok || seen[addr] {
+				continue
+			}
+
+			seen[addr] = true
+			ch <- prometheus.MustNewConstMetric(
+				ifaceAddressInfoDesc,
+				prometheus.GaugeValue,
+				float64(1),
+				stat.Name, addr.address, addr.family, addr.prefixLen,
+			)
+		}
+
+		if t, ok := c.ifaceAddresses[stat.Name]; ok {
+			ch <- prometheus.MustNewConstMetric(
+				ifaceAddressChangesDesc,
+				prometheus.CounterValue,
+				float64(t.changes),
+				stat.Name,
+			)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package collector
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultBackoffInitial   = 5 * time.Second
+	defaultBackoffMax       = 5 * time.Minute
+	defaultAuthFailureRetry = 30 * time.Minute
+)
+
+// Connection states, as exported by edgemax_exporter_backoff_state.
+const (
+	stateConnected  = "connected"
+	stateBackoff    = "backoff"
+	stateAuthFailed = "auth_failed"
+)
+
+var connectionStates = []string{stateConnected, stateBackoff, stateAuthFailed}
+
+// backoff is an exponential backoff with jitter.
+type backoff struct {
+	initial time.Duration
+	max     time.Duration
+	attempt uint
+}
+
+// next doubles the delay on every attempt, up to max, and randomizes it
+// between half and the full delay so targets do not retry in lockstep.
+// The attempts stop counting once max is reached, and max is shifted down
+// instead of initial up, so the delay can not overflow.
+func (b *backoff) next() time.Duration {
+	d := b.max
+	if b.initial <= b.max>>b.attempt {
+		d = b.initial << b.attempt
+		b.attempt++
+	}
+
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func (b *backoff) reset() {
+	b.attempt = 0
+}
+
+func (c *collector) setBackoff(state string, delay time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.exporterStats.backoffState = state
+	c.exporterStats.backoffDelay = delay
+}
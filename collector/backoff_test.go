@@ -0,0 +1,82 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffNext(t *testing.T) {
+	cases := []struct {
+		name     string
+		initial  time.Duration
+		max      time.Duration
+		attempts int
+		want     []time.Duration
+	}{
+		{
+			name:     "doubles up to max",
+			initial:  5 * time.Second,
+			max:      time.Minute,
+			attempts: 6,
+			want: []time.Duration{
+				5 * time.Second, 10 * time.Second, 20 * time.Second,
+				40 * time.Second, time.Minute, time.Minute,
+			},
+		},
+		{
+			name:     "many attempts",
+			initial:  5 * time.Second,
+			max:      5 * time.Minute,
+			attempts: 1000,
+		},
+		{
+			name:     "large initial",
+			initial:  time.Duration(1) << 62,
+			max:      time.Duration(1<<63 - 1),
+			attempts: 100,
+		},
+		{
+			name:     "max equals initial",
+			initial:  time.Minute,
+			max:      time.Minute,
+			attempts: 100,
+			want:     []time.Duration{time.Minute, time.Minute, time.Minute},
+		},
+		{
+			name:     "initial above max",
+			initial:  time.Hour,
+			max:      time.Minute,
+			attempts: 3,
+			want:     []time.Duration{time.Minute, time.Minute, time.Minute},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &backoff{initial: tc.initial, max: tc.max}
+
+			// The delay before jitter, doubled without overflowing
+			full := tc.initial
+			if full > tc.max {
+				full = tc.max
+			}
+
+			for i := 0; i < tc.attempts; i++ {
+				if i < len(tc.want) && full != tc.want[i] {
+					t.Fatalf("attempt %d: got %s, want %s", i, full, tc.want[i])
+				}
+
+				d := b.next()
+				if d < full/2 || d > full {
+					t.Fatalf("attempt %d: got %s, want between %s and %s", i, d, full/2, full)
+				}
+
+				if full > tc.max/2 {
+					full = tc.max
+				} else {
+					full *= 2
+				}
+			}
+		})
+	}
+}
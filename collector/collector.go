@@ -1,7 +1,9 @@
 package collector
 
 import (
+	"fmt"
 	"log"
+	"net"
 	"sync"
 	"time"
 
@@ -28,7 +30,7 @@ var (
 	ifaceLabelsDesc = prometheus.NewDesc(
 		prometheus.BuildFQName(ns, "interface", "labels"),
 		"Interface labels.", []string{
-			"interface", "mac",
+			"interface", "mac", "type", "vlan", "parent", "description",
 		}, nil,
 	)
 
@@ -88,16 +90,149 @@ var (
 	)
 )
 
+const (
+	defaultDPIMaxSeries = 1000
+)
+
+var (
+	errStopped = fmt.Errorf("collector stopped")
+
+	defaultTopics = []string{
+		api.TopicInterfaces,
+		api.TopicSystemStats,
+		api.TopicExport,
+		api.TopicDiscover,
+		api.TopicPONStats,
+		api.TopicNumRoutes,
+		api.TopicConfigChange,
+		api.TopicUsers,
+	}
+)
+
+// Options configures a collector.
+type Options struct {
+	// Topics to subscribe to. Defaults to every topic the collector exports.
+	Topics []string
+
+	// DPIMaxSeries caps the number of client/application pairs tracked from
+	// the "export" topic. New pairs are dropped once it is reached.
+	DPIMaxSeries int
+
+	// BackoffInitial and BackoffMax bound the exponential backoff between
+	// reconnection attempts.
+	BackoffInitial time.Duration
+	BackoffMax     time.Duration
+
+	// AuthFailureRetry is the delay before retrying after the router
+	// rejected the credentials, to avoid locking the account out.
+	AuthFailureRetry time.Duration
+
+	// StaleTTL is how long stats that are no longer reported, such as from
+	// a deleted interface, are still exported. Zero disables expiration.
+	StaleTTL time.Duration
+
+	// MonotonicCounters accumulates the interface counters across resets,
+	// such as after a reboot, instead of exporting them as reported.
+	MonotonicCounters bool
+
+	// PollInterval is how often the stats that are not published on the
+	// websocket, such as DHCP leases, are fetched.
+	PollInterval time.Duration
+
+	// DHCPLeaseInfo exports every DHCP lease, not only the pool usage.
+	// Leases are fetched either way, to name the DPI clients.
+	DHCPLeaseInfo bool
+
+	// RouteAllowlist selects the routes exported from the routing table by
+	// prefix. The routing table is not fetched when empty.
+	RouteAllowlist []*net.IPNet
+
+	// OnConfigChange, if set, is called for every config change event.
+	OnConfigChange func(*api.ConfigChange)
+}
+
+// Collector exports the stats received from a router subscription.
+type Collector interface {
+	prometheus.Collector
+
+	// Stop closes the subscription and stops reconnecting. The session is
+	// not logged out, it expires on the router.
+	Stop()
+
+	// Status reports the state of the router session and subscription.
+	Status() Status
+}
+
 type collector struct {
 	sync.RWMutex
 
-	*api.SystemStat
-	interfaceStat map[string]*api.InterfaceStat
+	opts Options
+	done chan struct{}
+	stop sync.Once
+
+	// configChanged tells the polling goroutine to reload the router config
+	configChanged chan struct{}
+
+	status        Status
+	exporterStats exporterStats
+
+	// Only used by the polling goroutine
+	retry backoff
+
+	stats
+
+	// session is incremented on every subscription, to tell reboots apart
+	// from counter wraps
+	session uint64
+
+	ifaceCounters  map[string]*ifaceCounters
+	ifaceAddresses map[string]*ifaceAddresses
+	ifaceConfig    map[string]*api.InterfaceConfig
+
+	dpiDropped uint64
+
+	configChanges    uint64
+	configLastChange time.Time
 }
 
-func New(c *api.Client) prometheus.Collector {
+func New(c *api.Client, opts Options) Collector {
+	if len(opts.Topics) == 0 {
+		opts.Topics = defaultTopics
+	}
+
+	if opts.DPIMaxSeries <= 0 {
+		opts.DPIMaxSeries = defaultDPIMaxSeries
+	}
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+
+	if opts.BackoffInitial <= 0 {
+		opts.BackoffInitial = defaultBackoffInitial
+	}
+
+	if opts.BackoffMax < opts.BackoffInitial {
+		opts.BackoffMax = defaultBackoffMax
+	}
+
+	if opts.AuthFailureRetry <= 0 {
+		opts.AuthFailureRetry = defaultAuthFailureRetry
+	}
+
 	ret := &collector{
-		interfaceStat: make(map[string]*api.InterfaceStat, 5),
+		opts:          opts,
+		done:          make(chan struct{}),
+		configChanged: make(chan struct{}, 1),
+		status:        Status{LastMessage: make(map[string]time.Time)},
+		exporterStats: newExporterStats(),
+		retry: backoff{
+			initial: opts.BackoffInitial,
+			max:     opts.BackoffMax,
+		},
+		stats:          newStats(),
+		ifaceCounters:  make(map[string]*ifaceCounters, 5),
+		ifaceAddresses: make(map[string]*ifaceAddresses, 5),
 	}
 
 	go ret.poolStatsFrom(c)
@@ -105,34 +240,58 @@ func New(c *api.Client) prometheus.Collector {
 	return ret
 }
 
+func (c *collector) Stop() {
+	c.stop.Do(func() {
+		close(c.done)
+	})
+}
+
 func (c *collector) poolStatsFrom(client *api.Client) {
 	for {
 		err := c.loginAndSubscribe(client)
-		log.Printf("error: %s", err)
+		if err == errStopped {
+			log.Printf("%s: Stopped.", client.Host)
+			return
+		}
+
+		log.Printf("error: %s: %s", client.Host, err)
+		c.setError(err)
+
+		delay, state := c.retry.next(), stateBackoff
+		if err == api.ErrAuthenticationFailed {
+			delay, state = c.opts.AuthFailureRetry, stateAuthFailed
+		}
 
-		log.Print("Reconnecting in 5 seconds...")
-		<-time.After(5 * time.Second)
+		c.setBackoff(state, delay)
+
+		log.Printf("%s: Reconnecting in %s...", client.Host, delay.Round(time.Second))
+		select {
+		case <-time.After(delay):
+			c.countReconnect()
+		case <-c.done:
+			log.Printf("%s: Stopped.", client.Host)
+			return
+		}
 	}
 }
 
 func (c *collector) loginAndSubscribe(client *api.Client) error {
 	if err := client.Login(); err != nil {
+		c.countLogin(loginFailure)
 		return err
 	}
 
-	log.Printf("Logged in as %s\n", client.Username)
+	c.countLogin(loginSuccess)
+
+	log.Printf("%s: Logged in as %s\n", client.Host, client.Username)
 	defer client.Close()
 
-	subs, err := client.Subscribe(
-		"interfaces",
-		"system-stats",
-		// "export",
-		// "discover",
-		// "pon-stats",
-		// "num-routes",
-		// "config-change",
-		// "users",
-	)
+	c.setLoggedIn(true)
+	defer c.setLoggedIn(false)
+
+	c.loadInterfaceConfig(client)
+
+	subs, err := client.Subscribe(c.opts.Topics...)
 
 	if err != nil {
 		return err
@@ -140,31 +299,105 @@ func (c *collector) loginAndSubscribe(client *api.Client) error {
 
 	defer subs.Stop()
 
-	for msg := range subs.C {
-		c.updateStats(msg)
-	}
+	c.setConnected(true)
+	defer c.setConnected(false)
+
+	// Nothing is reported while disconnected
+	defer c.resetStats()
+
+	c.loadSystemInfo(client)
 
-	return <-subs.Err
+	stopPolling, pollFailed := c.startPolling(client)
+	defer stopPolling()
+
+	c.retry.reset()
+	c.setBackoff(stateConnected, 0)
+
+	for {
+		select {
+		case msg, ok := <-subs.C:
+			if !ok {
+				return <-subs.Err
+			}
+
+			c.updateStats(msg)
+
+			// The hook runs without the lock, so it does not block scrapes
+			if m, ok := msg.(*api.ConfigChange); ok {
+				if c.opts.OnConfigChange != nil {
+					c.opts.OnConfigChange(m)
+				}
+
+				select {
+				case c.configChanged <- struct{}{}:
+				default:
+				}
+			}
+		case err := <-pollFailed:
+			return err
+		case <-c.done:
+			return errStopped
+		}
+	}
 }
 
-func (c *collector) updateStats(msg interface{}) {
+func (c *collector) updateStats(msg api.Message) {
 	c.Lock()
 	defer c.Unlock()
 
+	if err, ok := msg.(*api.DecodeError); ok {
+		log.Printf("decode error: %s", err)
+		c.exporterStats.decodeErrors[err.Topic()]++
+		return
+	}
+
+	now := time.Now()
+
+	c.status.LastMessage[msg.Topic()] = now
+	c.exporterStats.messages[msg.Topic()]++
+
 	switch m := msg.(type) {
 	case *api.SystemStat:
 		c.SystemStat = m
+		c.systemSeen = now
 	case *api.InterfaceStat:
-		c.interfaceStat[m.Name] = m
+		c.interfaceStat[m.Name] = c.trackCounters(m)
+		c.interfaceSeen[m.Name] = now
+		c.updateRates(m, now)
+		c.trackAddresses(m)
+	case *api.ExportStat:
+		c.updateDPIStat(m, now)
+	case *api.DiscoverStat:
+		c.neighborStat[m.MAC] = m
+		c.neighborSeen[m.MAC] = now
+	case *api.RouteCountStat:
+		c.routeCountStat = m
+	case *api.PONStat:
+		c.ponStat[m.Serial] = m
+		c.ponSeen[m.Serial] = now
+	case *api.UsersStat:
+		c.usersStat = m
+	case *api.ConfigChange:
+		c.updateConfigChange()
 	default:
 		log.Printf("unknown stats: %#v", m)
 	}
 }
 
 func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- upDesc
+	ch <- exporterLoginsDesc
+	ch <- exporterReconnectsDesc
+	ch <- exporterMessagesDesc
+	ch <- exporterDecodeErrorsDesc
+	ch <- exporterBackoffStateDesc
+	ch <- exporterBackoffDelayDesc
+	ch <- exporterLastMessageDesc
+
 	ch <- cpuUsageDesc
 	ch <- memUsageDesc
 	ch <- uptimeDesc
+	ch <- systemInfoDesc
 
 	ch <- ifaceLabelsDesc
 	ch <- ifaceUpDesc
@@ -178,6 +411,52 @@ func (c *collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- ifaceRxDroppedDesc
 	ch <- ifaceTxDroppedDesc
 	ch <- ifaceMulticastDesc
+	ch <- ifaceCounterResetsDesc
+	ch <- ifaceRxRateDesc
+	ch <- ifaceTxRateDesc
+	ch <- ifaceRateAvgDesc
+	ch <- ifaceAddressInfoDesc
+	ch <- ifaceAddressChangesDesc
+
+	ch <- dpiClientBytesDesc
+	ch <- dpiClientRateDesc
+	ch <- dpiDroppedDesc
+	ch <- dpiClientInfoDesc
+
+	ch <- neighborInfoDesc
+	ch <- neighborUptimeDesc
+
+	ch <- routesDesc
+	ch <- routesCountDesc
+	ch <- routeInfoDesc
+
+	ch <- ponLinkUpDesc
+	ch <- ponRxPowerDesc
+	ch <- ponTxPowerDesc
+	ch <- ponBiasCurrentDesc
+	ch <- ponTemperatureDesc
+	ch <- ponVoltageDesc
+
+	ch <- usersSessionsDesc
+	ch <- usersSessionInfoDesc
+	ch <- usersSessionDurationDesc
+
+	ch <- configChangesDesc
+	ch <- configLastChangeDesc
+
+	ch <- dhcpPoolSizeDesc
+	ch <- dhcpPoolLeasedDesc
+	ch <- dhcpPoolAvailableDesc
+	ch <- dhcpLeaseInfoDesc
+	ch <- dhcpLeaseExpiryDesc
+
+	ch <- defaultGatewayDesc
+	ch <- lbStatusDesc
+	ch <- lbStateDesc
+	ch <- lbCarrierDesc
+	ch <- lbPingsDesc
+	ch <- lbPingFailuresDesc
+	ch <- lbRouteDropsDesc
 }
 
 func (c *collector) collectInterfaceMetrics(ch chan<- prometheus.Metric) {
@@ -185,11 +464,12 @@ func (c *collector) collectInterfaceMetrics(ch chan<- prometheus.Metric) {
 	c.RLock()
 
 	for _, stat := range c.interfaceStat {
+		cfg := c.interfaceConfig(stat.Name)
 		ch <- prometheus.MustNewConstMetric(
 			ifaceLabelsDesc,
 			prometheus.GaugeValue,
 			float64(1),
-			stat.Name, stat.MAC,
+			stat.Name, stat.MAC, cfg.Type, cfg.VLAN, cfg.Parent, cfg.Description,
 		)
 
 		up := float64(0)
@@ -276,13 +556,13 @@ func (c *collector) collectInterfaceMetrics(ch chan<- prometheus.Metric) {
 }
 
 func (c *collector) collectSystemStats(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
 	if c.SystemStat == nil {
 		return
 	}
 
-	defer c.RUnlock()
-	c.RLock()
-
 	ch <- prometheus.MustNewConstMetric(
 		cpuUsageDesc,
 		prometheus.GaugeValue,
@@ -303,6 +583,21 @@ func (c *collector) collectSystemStats(ch chan<- prometheus.Metric) {
 }
 
 func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.expireStats()
+
+	c.collectExporterMetrics(ch)
 	c.collectSystemStats(ch)
+	c.collectSystemInfoMetrics(ch)
 	c.collectInterfaceMetrics(ch)
+	c.collectCounterResetMetrics(ch)
+	c.collectRateMetrics(ch)
+	c.collectAddressMetrics(ch)
+	c.collectDPIMetrics(ch)
+	c.collectNeighborMetrics(ch)
+	c.collectRouteMetrics(ch)
+	c.collectPONMetrics(ch)
+	c.collectUsersMetrics(ch)
+	c.collectConfigChangeMetrics(ch)
+	c.collectDHCPMetrics(ch)
+	c.collectLoadBalanceMetrics(ch)
 }
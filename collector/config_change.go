@@ -0,0 +1,46 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	configChangesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "config", "changes_total"),
+		"Configuration change events received.", nil, nil,
+	)
+
+	configLastChangeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "config", "last_change_timestamp_seconds"),
+		"Time of the last configuration change event (unix seconds).", nil, nil,
+	)
+)
+
+// updateConfigChange must be called with the lock held.
+func (c *collector) updateConfigChange() {
+	c.configChanges++
+	c.configLastChange = time.Now()
+}
+
+func (c *collector) collectConfigChangeMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	ch <- prometheus.MustNewConstMetric(
+		configChangesDesc,
+		prometheus.CounterValue,
+		float64(c.configChanges),
+	)
+
+	if c.configLastChange.IsZero() {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		configLastChangeDesc,
+		prometheus.GaugeValue,
+		float64(c.configLastChange.Unix()),
+	)
+}
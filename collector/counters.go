@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"log"
+
+	"github.com/juniorz/edgemax-exporter/api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	ifaceCounterResetsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "interface", "counter_resets_total"),
+		"Interface counter resets, such as after a reboot.", []string{"interface"}, nil,
+	)
+)
+
+// ifaceCounters tracks the counters of an interface across resets.
+// It outlives the stats, and is not expired, since a reboot also ends the
+// subscription and the router may be down for longer than StaleTTL.
+type ifaceCounters struct {
+	last    *api.InterfaceStat
+	offset  api.InterfaceStat
+	resets  uint64
+	session uint64
+}
+
+func counterFields(st *api.InterfaceStat) []*uint64 {
+	return []*uint64{
+		&st.RxPackets, &st.TxPackets,
+		&st.RxBytes, &st.TxBytes,
+		&st.RxErrors, &st.TxErrors,
+		&st.RxDropped, &st.TxDropped,
+		&st.Multicast,
+	}
+}
+
+// trackCounters detects counter resets and, with MonotonicCounters, returns
+// a copy of m with the counters accumulated across resets.
+// A counter going down in the first stats of a subscription is a reboot,
+// which resets the whole interface, since the busiest counters may already
+// be past their previous values. Afterwards, only the counters that went
+// down are offset, as they wrapped.
+// It must be called with the lock held.
+func (c *collector) trackCounters(m *api.InterfaceStat) *api.InterfaceStat {
+	t, ok := c.ifaceCounters[m.Name]
+	if !ok {
+		t = &ifaceCounters{}
+		c.ifaceCounters[m.Name] = t
+	}
+
+	offset := counterFields(&t.offset)
+
+	if t.last != nil {
+		last, cur := counterFields(t.last), counterFields(m)
+
+		reset := false
+		for i := range cur {
+			if *cur[i] < *last[i] {
+				reset = true
+				break
+			}
+		}
+
+		reboot := t.session != c.session
+
+		if reset {
+			log.Printf("%s: counters reset", m.Name)
+			t.resets++
+
+			for i := range offset {
+				if reboot || *cur[i] < *last[i] {
+					*offset[i] += *last[i]
+				}
+			}
+		}
+	}
+
+	t.last = m
+	t.session = c.session
+
+	if !c.opts.MonotonicCounters {
+		return m
+	}
+
+	acc := *m
+	for i, f := range counterFields(&acc) {
+		*f += *offset[i]
+	}
+
+	return &acc
+}
+
+func (c *collector) collectCounterResetMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	for name := range c.interfaceStat {
+		t, ok := c.ifaceCounters[name]
+		if !ok {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			ifaceCounterResetsDesc,
+			prometheus.CounterValue,
+			float64(t.resets),
+			name,
+		)
+	}
+}
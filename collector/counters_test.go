@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/juniorz/edgemax-exporter/api"
+)
+
+type counterSample struct {
+	session uint64
+	stat    api.InterfaceStat
+}
+
+func TestTrackCounters(t *testing.T) {
+	cases := []struct {
+		name    string
+		samples []counterSample
+		want    api.InterfaceStat
+		resets  uint64
+	}{
+		{
+			name: "increasing",
+			samples: []counterSample{
+				{1, api.InterfaceStat{RxBytes: 100, TxBytes: 50, RxPackets: 10}},
+				{1, api.InterfaceStat{RxBytes: 200, TxBytes: 70, RxPackets: 20}},
+			},
+			want: api.InterfaceStat{RxBytes: 200, TxBytes: 70, RxPackets: 20},
+		},
+		{
+			name: "reconnect without reboot",
+			samples: []counterSample{
+				{1, api.InterfaceStat{RxBytes: 100, TxBytes: 50}},
+				{2, api.InterfaceStat{RxBytes: 150, TxBytes: 60}},
+			},
+			want: api.InterfaceStat{RxBytes: 150, TxBytes: 60},
+		},
+		{
+			// TxBytes is already past its previous value when first reported
+			name: "reboot",
+			samples: []counterSample{
+				{1, api.InterfaceStat{RxBytes: 1000, TxBytes: 500, RxPackets: 10, TxPackets: 5}},
+				{2, api.InterfaceStat{RxBytes: 100, TxBytes: 600, RxPackets: 1, TxPackets: 6}},
+				{2, api.InterfaceStat{RxBytes: 150, TxBytes: 650, RxPackets: 2, TxPackets: 7}},
+			},
+			want:   api.InterfaceStat{RxBytes: 1150, TxBytes: 1150, RxPackets: 12, TxPackets: 12},
+			resets: 1,
+		},
+		{
+			name: "single field wrap",
+			samples: []counterSample{
+				{1, api.InterfaceStat{RxBytes: 4294967000, TxBytes: 100}},
+				{1, api.InterfaceStat{RxBytes: 200, TxBytes: 120}},
+			},
+			want:   api.InterfaceStat{RxBytes: 4294967200, TxBytes: 120},
+			resets: 1,
+		},
+		{
+			name: "interface recreated",
+			samples: []counterSample{
+				{1, api.InterfaceStat{RxBytes: 1000, TxBytes: 500, Multicast: 3}},
+				{1, api.InterfaceStat{RxBytes: 0, TxBytes: 0, Multicast: 0}},
+				{1, api.InterfaceStat{RxBytes: 10, TxBytes: 5, Multicast: 1}},
+			},
+			want:   api.InterfaceStat{RxBytes: 1010, TxBytes: 505, Multicast: 4},
+			resets: 1,
+		},
+		{
+			name: "several resets",
+			samples: []counterSample{
+				{1, api.InterfaceStat{RxBytes: 1000}},
+				{1, api.InterfaceStat{RxBytes: 10}},
+				{2, api.InterfaceStat{RxBytes: 5}},
+			},
+			want:   api.InterfaceStat{RxBytes: 1015},
+			resets: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &collector{
+				opts:          Options{MonotonicCounters: true},
+				ifaceCounters: make(map[string]*ifaceCounters),
+			}
+
+			var got *api.InterfaceStat
+			for _, s := range tc.samples {
+				c.session = s.session
+
+				st := s.stat
+				st.Name = "eth0"
+				got = c.trackCounters(&st)
+			}
+
+			want := tc.want
+			want.Name = "eth0"
+
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("got %+v, want %+v", *got, want)
+			}
+
+			if n := c.ifaceCounters["eth0"].resets; n != tc.resets {
+				t.Errorf("got %d resets, want %d", n, tc.resets)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package collector
+
+import (
+	"github.com/juniorz/edgemax-exporter/api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	dhcpPoolSizeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "dhcp", "pool_size"),
+		"DHCP server pool addresses.", []string{"pool"}, nil,
+	)
+
+	dhcpPoolLeasedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "dhcp", "pool_leased"),
+		"DHCP server pool leased addresses.", []string{"pool"}, nil,
+	)
+
+	dhcpPoolAvailableDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "dhcp", "pool_available"),
+		"DHCP server pool available addresses.", []string{"pool"}, nil,
+	)
+
+	dhcpLeaseInfoDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "dhcp", "lease_info"),
+		"DHCP server lease.", []string{
+			"pool", "ip", "mac", "hostname",
+		}, nil,
+	)
+
+	dhcpLeaseExpiryDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "dhcp", "lease_expiry_timestamp_seconds"),
+		"DHCP server lease expiration (unix seconds).", []string{"pool", "ip"}, nil,
+	)
+)
+
+func (c *collector) pollDHCP(client *api.Client) error {
+	pools, err := client.DHCPStats()
+	if err != nil {
+		return err
+	}
+
+	leases, err := client.DHCPLeases()
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.dhcpPools = pools
+	c.dhcpLeases = leases
+
+	return nil
+}
+
+func (c *collector) collectDHCPMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	for _, stat := range c.dhcpPools {
+		ch <- prometheus.MustNewConstMetric(
+			dhcpPoolSizeDesc,
+			prometheus.GaugeValue,
+			float64(stat.Size),
+			stat.Pool,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			dhcpPoolLeasedDesc,
+			prometheus.GaugeValue,
+			float64(stat.Leased),
+			stat.Pool,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			dhcpPoolAvailableDesc,
+			prometheus.GaugeValue,
+			float64(stat.Available),
+			stat.Pool,
+		)
+	}
+
+	if !c.opts.DHCPLeaseInfo {
+		return
+	}
+
+	for _, l := range c.dhcpLeases {
+		ch <- prometheus.MustNewConstMetric(
+			dhcpLeaseInfoDesc,
+			prometheus.GaugeValue,
+			float64(1),
+			l.Pool, l.IP, l.MAC, l.Hostname,
+		)
+
+		if l.Expiration.IsZero() {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			dhcpLeaseExpiryDesc,
+			prometheus.GaugeValue,
+			float64(l.Expiration.Unix()),
+			l.Pool, l.IP,
+		)
+	}
+}
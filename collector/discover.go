@@ -0,0 +1,40 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	neighborInfoDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "neighbor", "info"),
+		"UBNT discovery neighbor.", []string{
+			"mac", "ip", "hostname", "model", "firmware",
+		}, nil,
+	)
+
+	neighborUptimeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "neighbor", "uptime_seconds"),
+		"UBNT discovery neighbor uptime (seconds).", []string{"mac"}, nil,
+	)
+)
+
+func (c *collector) collectNeighborMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	for _, stat := range c.neighborStat {
+		ch <- prometheus.MustNewConstMetric(
+			neighborInfoDesc,
+			prometheus.GaugeValue,
+			float64(1),
+			stat.MAC, stat.IP, stat.Hostname, stat.Model, stat.Firmware,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			neighborUptimeDesc,
+			prometheus.GaugeValue,
+			float64(stat.Uptime),
+			stat.MAC,
+		)
+	}
+}
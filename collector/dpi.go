@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"log"
+	"time"
+
+	"github.com/juniorz/edgemax-exporter/api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	dpiClientBytesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "dpi", "client_bytes_total"),
+		"DPI bytes per client and application.", []string{
+			"client", "app", "category", "direction",
+		}, nil,
+	)
+
+	dpiClientRateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "dpi", "client_bytes_per_second"),
+		"DPI rate per client and application (bytes per second).", []string{
+			"client", "app", "category", "direction",
+		}, nil,
+	)
+
+	dpiDroppedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "dpi", "updates_dropped_total"),
+		"DPI updates dropped for client/application pairs over the series limit.", nil, nil,
+	)
+
+	dpiClientInfoDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "dpi", "client_info"),
+		"Host name of a DPI client, from the DHCP server leases.", []string{
+			"client", "hostname",
+		}, nil,
+	)
+)
+
+type dpiKey struct {
+	client      string
+	category    string
+	application string
+}
+
+// updateDPIStat must be called with the lock held.
+// New pairs are dropped once the limit is reached, so the tracked series stay
+// stable, until the existing ones expire after StaleTTL.
+func (c *collector) updateDPIStat(m *api.ExportStat, now time.Time) {
+	k := dpiKey{m.Client, m.Category, m.Application}
+
+	if _, ok := c.dpiStat[k]; !ok && len(c.dpiStat) >= c.opts.DPIMaxSeries {
+		if c.dpiDropped == 0 {
+			log.Printf("DPI series limit (%d) reached, dropping new entries", c.opts.DPIMaxSeries)
+		}
+
+		c.dpiDropped++
+		return
+	}
+
+	c.dpiStat[k] = m
+	c.dpiSeen[k] = now
+}
+
+func (c *collector) collectDPIMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	ch <- prometheus.MustNewConstMetric(
+		dpiDroppedDesc,
+		prometheus.CounterValue,
+		float64(c.dpiDropped),
+	)
+
+	hostnames := make(map[string]string, len(c.dhcpLeases))
+	for _, l := range c.dhcpLeases {
+		if l.Hostname != "" {
+			hostnames[l.IP] = l.Hostname
+		}
+	}
+
+	clients := make(map[string]bool)
+
+	for _, stat := range c.dpiStat {
+		if h, ok := hostnames[stat.Client]; ok && !clients[stat.Client] {
+			clients[stat.Client] = true
+			ch <- prometheus.MustNewConstMetric(
+				dpiClientInfoDesc,
+				prometheus.GaugeValue,
+				float64(1),
+				stat.Client, h,
+			)
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			dpiClientBytesDesc,
+			prometheus.CounterValue,
+			float64(stat.RxBytes),
+			stat.Client, stat.Application, stat.Category, "rx",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			dpiClientBytesDesc,
+			prometheus.CounterValue,
+			float64(stat.TxBytes),
+			stat.Client, stat.Application, stat.Category, "tx",
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			dpiClientRateDesc,
+			prometheus.GaugeValue,
+			float64(stat.RxRate),
+			stat.Client, stat.Application, stat.Category, "rx",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			dpiClientRateDesc,
+			prometheus.GaugeValue,
+			float64(stat.TxRate),
+			stat.Client, stat.Application, stat.Category, "tx",
+		)
+	}
+}
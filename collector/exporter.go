@@ -0,0 +1,167 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	upDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "", "up"),
+		"The router session is logged in and subscribed.", nil, nil,
+	)
+
+	exporterLoginsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "exporter", "logins_total"),
+		"Login attempts by result.", []string{"result"}, nil,
+	)
+
+	exporterReconnectsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "exporter", "reconnects_total"),
+		"Reconnections after the session or subscription ended.", nil, nil,
+	)
+
+	exporterMessagesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "exporter", "messages_received_total"),
+		"Messages received per topic.", []string{"topic"}, nil,
+	)
+
+	exporterDecodeErrorsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "exporter", "decode_errors_total"),
+		"Topic payloads that could not be decoded.", []string{"topic"}, nil,
+	)
+
+	exporterBackoffStateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "exporter", "backoff_state"),
+		"Connection state: connected, backoff or auth_failed.", []string{"state"}, nil,
+	)
+
+	exporterBackoffDelayDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "exporter", "backoff_seconds"),
+		"Delay before the next reconnection attempt (seconds).", nil, nil,
+	)
+
+	exporterLastMessageDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "exporter", "last_message_timestamp_seconds"),
+		"Time of the last message received per topic (unix seconds).", []string{"topic"}, nil,
+	)
+)
+
+const (
+	loginSuccess = "success"
+	loginFailure = "failure"
+)
+
+// exporterStats are the exporter's own counters.
+type exporterStats struct {
+	logins       map[string]uint64
+	reconnects   uint64
+	messages     map[string]uint64
+	decodeErrors map[string]uint64
+
+	backoffState string
+	backoffDelay time.Duration
+}
+
+func newExporterStats() exporterStats {
+	return exporterStats{
+		logins: map[string]uint64{
+			loginSuccess: 0,
+			loginFailure: 0,
+		},
+		messages:     make(map[string]uint64),
+		decodeErrors: make(map[string]uint64),
+		backoffState: stateBackoff,
+	}
+}
+
+func (c *collector) countLogin(result string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.exporterStats.logins[result]++
+}
+
+func (c *collector) countReconnect() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.exporterStats.reconnects++
+}
+
+func (c *collector) collectExporterMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	up := float64(0)
+	if c.status.LoggedIn && c.status.Connected {
+		up = 1
+	}
+	ch <- prometheus.MustNewConstMetric(
+		upDesc,
+		prometheus.GaugeValue,
+		up,
+	)
+
+	for result, n := range c.exporterStats.logins {
+		ch <- prometheus.MustNewConstMetric(
+			exporterLoginsDesc,
+			prometheus.CounterValue,
+			float64(n),
+			result,
+		)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		exporterReconnectsDesc,
+		prometheus.CounterValue,
+		float64(c.exporterStats.reconnects),
+	)
+
+	for _, state := range connectionStates {
+		v := float64(0)
+		if state == c.exporterStats.backoffState {
+			v = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			exporterBackoffStateDesc,
+			prometheus.GaugeValue,
+			v,
+			state,
+		)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		exporterBackoffDelayDesc,
+		prometheus.GaugeValue,
+		c.exporterStats.backoffDelay.Seconds(),
+	)
+
+	for topic, n := range c.exporterStats.messages {
+		ch <- prometheus.MustNewConstMetric(
+			exporterMessagesDesc,
+			prometheus.CounterValue,
+			float64(n),
+			topic,
+		)
+	}
+
+	for topic, n := range c.exporterStats.decodeErrors {
+		ch <- prometheus.MustNewConstMetric(
+			exporterDecodeErrorsDesc,
+			prometheus.CounterValue,
+			float64(n),
+			topic,
+		)
+	}
+
+	for topic, t := range c.status.LastMessage {
+		ch <- prometheus.MustNewConstMetric(
+			exporterLastMessageDesc,
+			prometheus.GaugeValue,
+			float64(t.UnixNano())/1e9,
+			topic,
+		)
+	}
+}
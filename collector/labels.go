@@ -0,0 +1,73 @@
+package collector
+
+import (
+	"log"
+	"strings"
+
+	"github.com/juniorz/edgemax-exporter/api"
+)
+
+// ifaceTypes classify interfaces missing from the router config by name.
+var ifaceTypes = []struct {
+	prefix string
+	t      string
+}{
+	{"eth", "ethernet"},
+	{"switch", "switch"},
+	{"br", "bridge"},
+	{"pppoe", "pppoe"},
+	{"wg", "wireguard"},
+	{"vtun", "openvpn"},
+	{"vti", "vti"},
+	{"tun", "tunnel"},
+	{"l2tp", "l2tp"},
+	{"lo", "loopback"},
+}
+
+func classifyInterface(name string) *api.InterfaceConfig {
+	ret := &api.InterfaceConfig{
+		Name: name,
+		Type: "other",
+	}
+
+	if i := strings.LastIndex(name, "."); i > 0 {
+		ret.Type = "vlan"
+		ret.VLAN = name[i+1:]
+		ret.Parent = name[:i]
+		return ret
+	}
+
+	for _, t := range ifaceTypes {
+		if strings.HasPrefix(name, t.prefix) {
+			ret.Type = t.t
+			break
+		}
+	}
+
+	return ret
+}
+
+// interfaceConfig must be called with the lock held.
+func (c *collector) interfaceConfig(name string) *api.InterfaceConfig {
+	if cfg, ok := c.ifaceConfig[name]; ok {
+		return cfg
+	}
+
+	return classifyInterface(name)
+}
+
+// loadInterfaceConfig fetches the interface descriptions, VLANs and parents
+// from the router config. Interfaces are still classified by name if it
+// fails.
+func (c *collector) loadInterfaceConfig(client *api.Client) {
+	cfg, err := client.Interfaces()
+	if err != nil {
+		log.Printf("error: %s: interfaces config: %s", client.Host, err)
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.ifaceConfig = cfg
+}
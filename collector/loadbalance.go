@@ -0,0 +1,162 @@
+package collector
+
+import (
+	"github.com/juniorz/edgemax-exporter/api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Load-balance interface states, as exported by
+// edgemax_loadbalance_interface_state.
+var loadBalanceStates = []string{"active", "failover", "inactive"}
+
+var (
+	defaultGatewayDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "", "default_gateway_info"),
+		"Gateway of the default route in use.", []string{
+			"gateway", "interface",
+		}, nil,
+	)
+
+	lbStatusDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "loadbalance", "interface_status"),
+		"Whether the load-balance interface is healthy (carrier up and ping watchdog OK).", []string{
+			"group", "interface",
+		}, nil,
+	)
+
+	lbStateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "loadbalance", "interface_state"),
+		"Load-balance interface state (active, failover or inactive).", []string{
+			"group", "interface", "state",
+		}, nil,
+	)
+
+	lbCarrierDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "loadbalance", "interface_carrier_up"),
+		"Whether the load-balance interface has carrier.", []string{
+			"group", "interface",
+		}, nil,
+	)
+
+	lbPingsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "loadbalance", "pings_total"),
+		"Load-balance watchdog pings.", []string{
+			"group", "interface",
+		}, nil,
+	)
+
+	lbPingFailuresDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "loadbalance", "ping_failures_total"),
+		"Load-balance watchdog ping failures.", []string{
+			"group", "interface",
+		}, nil,
+	)
+
+	lbRouteDropsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "loadbalance", "route_drops_total"),
+		"Times the load-balance watchdog removed the interface routes.", []string{
+			"group", "interface",
+		}, nil,
+	)
+)
+
+func (c *collector) pollDefaultGateway(client *api.Client) error {
+	gw, err := client.DefaultGateway()
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.defaultGateway = gw
+
+	return nil
+}
+
+func (c *collector) pollLoadBalance(client *api.Client) error {
+	lb, err := client.LoadBalanceStatus()
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.loadBalanceStat = lb
+
+	return nil
+}
+
+func (c *collector) collectLoadBalanceMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	if c.defaultGateway != nil && c.defaultGateway.Gateway != "" {
+		ch <- prometheus.MustNewConstMetric(
+			defaultGatewayDesc,
+			prometheus.GaugeValue,
+			float64(1),
+			c.defaultGateway.Gateway, c.defaultGateway.Interface,
+		)
+	}
+
+	for _, stat := range c.loadBalanceStat {
+		healthy := 0
+		if stat.Carrier && stat.PingOK {
+			healthy = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			lbStatusDesc,
+			prometheus.GaugeValue,
+			float64(healthy),
+			stat.Group, stat.Interface,
+		)
+
+		for _, s := range loadBalanceStates {
+			v := 0
+			if stat.Status == s {
+				v = 1
+			}
+
+			ch <- prometheus.MustNewConstMetric(
+				lbStateDesc,
+				prometheus.GaugeValue,
+				float64(v),
+				stat.Group, stat.Interface, s,
+			)
+		}
+
+		carrier := 0
+		if stat.Carrier {
+			carrier = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			lbCarrierDesc,
+			prometheus.GaugeValue,
+			float64(carrier),
+			stat.Group, stat.Interface,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			lbPingsDesc,
+			prometheus.CounterValue,
+			float64(stat.Pings),
+			stat.Group, stat.Interface,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			lbPingFailuresDesc,
+			prometheus.CounterValue,
+			float64(stat.Fails),
+			stat.Group, stat.Interface,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			lbRouteDropsDesc,
+			prometheus.CounterValue,
+			float64(stat.RouteDrops),
+			stat.Group, stat.Interface,
+		)
+	}
+}
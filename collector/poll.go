@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/juniorz/edgemax-exporter/api"
+)
+
+const (
+	defaultPollInterval = 30 * time.Second
+)
+
+// pollers fetch the stats that are not published on the websocket.
+var pollers = []struct {
+	name string
+	poll func(*collector, *api.Client) error
+}{
+	{"dhcp", (*collector).pollDHCP},
+	{"default-gateway", (*collector).pollDefaultGateway},
+	{"load-balance", (*collector).pollLoadBalance},
+	{"routes", (*collector).pollRoutes},
+}
+
+// startPolling runs the pollers every PollInterval until the returned
+// function is called, which waits for them to finish.
+// Polling stops, and the error is sent on failed, when the router rejects
+// the credentials, so the session ends and is retried as a failed login.
+// On config changes, the router config is reloaded and the pollers run
+// again, so the subscription is not blocked by the requests.
+func (c *collector) startPolling(client *api.Client) (stop func(), failed <-chan error) {
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	authFailed := make(chan error, 1)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		t := time.NewTicker(c.opts.PollInterval)
+		defer t.Stop()
+
+		for {
+			for _, p := range pollers {
+				err := p.poll(c, client)
+				if err == api.ErrAuthenticationFailed {
+					authFailed <- err
+					return
+				}
+
+				if err != nil {
+					log.Printf("error: %s: %s: %s", client.Host, p.name, err)
+				}
+			}
+
+			select {
+			case <-t.C:
+			case <-c.configChanged:
+				c.loadInterfaceConfig(client)
+				c.loadSystemInfo(client)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	stop = func() {
+		close(done)
+		wg.Wait()
+	}
+
+	return stop, authFailed
+}
@@ -0,0 +1,91 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	ponLabels = []string{"serial", "port"}
+
+	ponLinkUpDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "pon", "onu_link_up"),
+		"ONU link is UP.", ponLabels, nil,
+	)
+
+	ponRxPowerDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "pon", "onu_rx_power_dbm"),
+		"ONU optical received power (dBm).", ponLabels, nil,
+	)
+
+	ponTxPowerDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "pon", "onu_tx_power_dbm"),
+		"ONU optical transmitted power (dBm).", ponLabels, nil,
+	)
+
+	ponBiasCurrentDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "pon", "onu_bias_current_amperes"),
+		"ONU laser bias current (amperes).", ponLabels, nil,
+	)
+
+	ponTemperatureDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "pon", "onu_temperature_celsius"),
+		"ONU transceiver temperature (celsius).", ponLabels, nil,
+	)
+
+	ponVoltageDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "pon", "onu_voltage_volts"),
+		"ONU transceiver supply voltage (volts).", ponLabels, nil,
+	)
+)
+
+func (c *collector) collectPONMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	for _, stat := range c.ponStat {
+		up := float64(0)
+		if stat.LinkUp {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			ponLinkUpDesc,
+			prometheus.GaugeValue,
+			up,
+			stat.Serial, stat.Port,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			ponRxPowerDesc,
+			prometheus.GaugeValue,
+			stat.RxPower,
+			stat.Serial, stat.Port,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			ponTxPowerDesc,
+			prometheus.GaugeValue,
+			stat.TxPower,
+			stat.Serial, stat.Port,
+		)
+
+		// Reported in mA
+		ch <- prometheus.MustNewConstMetric(
+			ponBiasCurrentDesc,
+			prometheus.GaugeValue,
+			stat.BiasCurrent/1000,
+			stat.Serial, stat.Port,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			ponTemperatureDesc,
+			prometheus.GaugeValue,
+			stat.Temperature,
+			stat.Serial, stat.Port,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			ponVoltageDesc,
+			prometheus.GaugeValue,
+			stat.Voltage,
+			stat.Serial, stat.Port,
+		)
+	}
+}
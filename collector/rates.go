@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"math"
+	"time"
+
+	"github.com/juniorz/edgemax-exporter/api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	ifaceRxRateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "interface", "rx_bytes_per_second"),
+		"Interface receive rate reported by the router (bytes per second).", []string{"interface"}, nil,
+	)
+
+	ifaceTxRateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "interface", "tx_bytes_per_second"),
+		"Interface transmit rate reported by the router (bytes per second).", []string{"interface"}, nil,
+	)
+
+	ifaceRateAvgDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "interface", "bytes_per_second_avg"),
+		"Interface rate exponentially averaged over a window (bytes per second).", []string{
+			"interface", "direction", "window",
+		}, nil,
+	)
+)
+
+// rateWindows are the averaging windows, as in load averages.
+var rateWindows = [...]struct {
+	name string
+	d    time.Duration
+}{
+	{"1m", time.Minute},
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+}
+
+type ewma [len(rateWindows)]float64
+
+// update moves each average towards v, weighted by the time elapsed since
+// the previous sample so irregular updates are accounted for.
+func (e *ewma) update(v float64, elapsed time.Duration) {
+	for i, w := range rateWindows {
+		alpha := 1 - math.Exp(-elapsed.Seconds()/w.d.Seconds())
+		e[i] += alpha * (v - e[i])
+	}
+}
+
+func (e *ewma) reset(v float64) {
+	for i := range e {
+		e[i] = v
+	}
+}
+
+// ifaceRates are the averaged rates of an interface.
+type ifaceRates struct {
+	rx, tx ewma
+	last   time.Time
+}
+
+// updateRates must be called with the lock held.
+func (c *collector) updateRates(m *api.InterfaceStat, now time.Time) {
+	r, ok := c.interfaceRates[m.Name]
+	if !ok {
+		r = &ifaceRates{}
+		r.rx.reset(float64(m.RxBytesPerSec))
+		r.tx.reset(float64(m.TxBytesPerSec))
+		r.last = now
+
+		c.interfaceRates[m.Name] = r
+		return
+	}
+
+	elapsed := now.Sub(r.last)
+	r.rx.update(float64(m.RxBytesPerSec), elapsed)
+	r.tx.update(float64(m.TxBytesPerSec), elapsed)
+	r.last = now
+}
+
+func (c *collector) collectRateMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	for _, stat := range c.interfaceStat {
+		ch <- prometheus.MustNewConstMetric(
+			ifaceRxRateDesc,
+			prometheus.GaugeValue,
+			float64(stat.RxBytesPerSec),
+			stat.Name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			ifaceTxRateDesc,
+			prometheus.GaugeValue,
+			float64(stat.TxBytesPerSec),
+			stat.Name,
+		)
+
+		r, ok := c.interfaceRates[stat.Name]
+		if !ok {
+			continue
+		}
+
+		for i, w := range rateWindows {
+			ch <- prometheus.MustNewConstMetric(
+				ifaceRateAvgDesc,
+				prometheus.GaugeValue,
+				r.rx[i],
+				stat.Name, "rx", w.name,
+			)
+			ch <- prometheus.MustNewConstMetric(
+				ifaceRateAvgDesc,
+				prometheus.GaugeValue,
+				r.tx[i],
+				stat.Name, "tx", w.name,
+			)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package collector
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/juniorz/edgemax-exporter/api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	routesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "", "routes"),
+		"Number of routes per protocol.", []string{"protocol"}, nil,
+	)
+
+	routesCountDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "routes", "count"),
+		"Total number of routes in the routing table, installed or not.", nil, nil,
+	)
+
+	routeInfoDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "route", "info"),
+		"Route installed in the forwarding table, for the prefixes in the allowlist.", []string{
+			"prefix", "nexthop", "interface", "protocol", "distance", "metric",
+		}, nil,
+	)
+)
+
+// routeAllowed reports whether prefix is within any of the networks.
+func routeAllowed(prefix string, networks []*net.IPNet) bool {
+	ip, n, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return false
+	}
+
+	ones, bits := n.Mask.Size()
+	for _, allowed := range networks {
+		allowedOnes, allowedBits := allowed.Mask.Size()
+		if allowedBits == bits && allowedOnes <= ones && allowed.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pollRoutes fetches the routing table. It is skipped when the allowlist is
+// empty, as the table may be large.
+func (c *collector) pollRoutes(client *api.Client) error {
+	if len(c.opts.RouteAllowlist) == 0 {
+		return nil
+	}
+
+	routes, err := client.Routes()
+	if err != nil {
+		return err
+	}
+
+	// Duplicate entries would be rejected by the registry
+	seen := make(map[api.Route]bool)
+
+	allowed := routes[:0]
+	for _, r := range routes {
+		if seen[*r] || !routeAllowed(r.Prefix, c.opts.RouteAllowlist) {
+			continue
+		}
+
+		seen[*r] = true
+		allowed = append(allowed, r)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.routes = allowed
+
+	return nil
+}
+
+func (c *collector) collectRouteMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	for _, r := range c.routes {
+		ch <- prometheus.MustNewConstMetric(
+			routeInfoDesc,
+			prometheus.GaugeValue,
+			float64(1),
+			r.Prefix, r.NextHop, r.Interface, r.Protocol,
+			strconv.FormatUint(r.Distance, 10),
+			strconv.FormatUint(r.Metric, 10),
+		)
+	}
+
+	if c.routeCountStat == nil {
+		return
+	}
+
+	for protocol, n := range c.routeCountStat.Protocols {
+		ch <- prometheus.MustNewConstMetric(
+			routesDesc,
+			prometheus.GaugeValue,
+			float64(n),
+			protocol,
+		)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		routesCountDesc,
+		prometheus.GaugeValue,
+		float64(c.routeCountStat.Total),
+	)
+}
@@ -0,0 +1,109 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/juniorz/edgemax-exporter/api"
+)
+
+// stats is the state reported by the router, along with when each entry
+// was last reported.
+type stats struct {
+	*api.SystemStat
+	systemSeen time.Time
+
+	systemInfo *api.SystemInfo
+
+	interfaceStat map[string]*api.InterfaceStat
+	interfaceSeen map[string]time.Time
+
+	interfaceRates map[string]*ifaceRates
+
+	dpiStat map[dpiKey]*api.ExportStat
+	dpiSeen map[dpiKey]time.Time
+
+	neighborStat map[string]*api.DiscoverStat
+	neighborSeen map[string]time.Time
+
+	routeCountStat *api.RouteCountStat
+	routes         []*api.Route
+
+	ponStat map[string]*api.PONStat
+	ponSeen map[string]time.Time
+
+	usersStat *api.UsersStat
+
+	dhcpPools  []*api.DHCPPoolStat
+	dhcpLeases []*api.DHCPLease
+
+	defaultGateway  *api.DefaultGateway
+	loadBalanceStat []*api.LoadBalanceInterface
+}
+
+func newStats() stats {
+	return stats{
+		interfaceStat:  make(map[string]*api.InterfaceStat, 5),
+		interfaceSeen:  make(map[string]time.Time, 5),
+		interfaceRates: make(map[string]*ifaceRates, 5),
+		dpiStat:        make(map[dpiKey]*api.ExportStat),
+		dpiSeen:        make(map[dpiKey]time.Time),
+		neighborStat:   make(map[string]*api.DiscoverStat),
+		neighborSeen:   make(map[string]time.Time),
+		ponStat:        make(map[string]*api.PONStat),
+		ponSeen:        make(map[string]time.Time),
+	}
+}
+
+// resetStats forgets everything reported by the router, so Prometheus
+// sees the series go stale while disconnected.
+func (c *collector) resetStats() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.stats = newStats()
+}
+
+// expireStats forgets the entries not reported in the last StaleTTL.
+func (c *collector) expireStats() {
+	if c.opts.StaleTTL <= 0 {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	deadline := time.Now().Add(-c.opts.StaleTTL)
+
+	if c.systemSeen.Before(deadline) {
+		c.SystemStat = nil
+	}
+
+	for k, seen := range c.interfaceSeen {
+		if seen.Before(deadline) {
+			delete(c.interfaceStat, k)
+			delete(c.interfaceSeen, k)
+			delete(c.interfaceRates, k)
+		}
+	}
+
+	for k, seen := range c.dpiSeen {
+		if seen.Before(deadline) {
+			delete(c.dpiStat, k)
+			delete(c.dpiSeen, k)
+		}
+	}
+
+	for k, seen := range c.neighborSeen {
+		if seen.Before(deadline) {
+			delete(c.neighborStat, k)
+			delete(c.neighborSeen, k)
+		}
+	}
+
+	for k, seen := range c.ponSeen {
+		if seen.Before(deadline) {
+			delete(c.ponStat, k)
+			delete(c.ponSeen, k)
+		}
+	}
+}
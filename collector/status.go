@@ -0,0 +1,69 @@
+package collector
+
+import (
+	"time"
+)
+
+// Status is the state of the router session and subscription.
+type Status struct {
+	LoggedIn  bool `json:"logged_in"`
+	Connected bool `json:"connected"`
+
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorTime *time.Time `json:"last_error_time,omitempty"`
+
+	// LastMessage is the time of the last message received per topic.
+	LastMessage map[string]time.Time `json:"last_message"`
+}
+
+// LastMessageTime is the time of the last message received from any topic.
+func (s Status) LastMessageTime() time.Time {
+	var last time.Time
+	for _, t := range s.LastMessage {
+		if t.After(last) {
+			last = t
+		}
+	}
+
+	return last
+}
+
+func (c *collector) Status() Status {
+	defer c.RUnlock()
+	c.RLock()
+
+	ret := c.status
+	ret.LastMessage = make(map[string]time.Time, len(c.status.LastMessage))
+	for k, v := range c.status.LastMessage {
+		ret.LastMessage[k] = v
+	}
+
+	return ret
+}
+
+func (c *collector) setLoggedIn(v bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.status.LoggedIn = v
+}
+
+func (c *collector) setConnected(v bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.status.Connected = v
+
+	if v {
+		c.session++
+	}
+}
+
+func (c *collector) setError(err error) {
+	c.Lock()
+	defer c.Unlock()
+
+	now := time.Now()
+	c.status.LastError = err.Error()
+	c.status.LastErrorTime = &now
+}
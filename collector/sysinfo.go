@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"log"
+
+	"github.com/juniorz/edgemax-exporter/api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	systemInfoDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "system", "info"),
+		"Router model and firmware.", []string{
+			"model", "version", "build", "serial", "hostname",
+		}, nil,
+	)
+)
+
+// loadSystemInfo fetches the model and firmware, which only change on
+// upgrade, so it is done once per session and on config changes.
+func (c *collector) loadSystemInfo(client *api.Client) {
+	info, err := client.SystemInfo()
+	if err != nil {
+		log.Printf("error: %s: system info: %s", client.Host, err)
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.systemInfo = info
+}
+
+func (c *collector) collectSystemInfoMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	if c.systemInfo == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		systemInfoDesc,
+		prometheus.GaugeValue,
+		float64(1),
+		c.systemInfo.Model,
+		c.systemInfo.Version,
+		c.systemInfo.Build,
+		c.systemInfo.Serial,
+		c.systemInfo.Hostname,
+	)
+}
@@ -0,0 +1,75 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	usersSessionsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "users", "sessions"),
+		"Active login sessions per type.", []string{"type"}, nil,
+	)
+
+	usersSessionInfoDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "users", "session_info"),
+		"Active login session.", []string{
+			"type", "user", "address",
+		}, nil,
+	)
+
+	usersSessionDurationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(ns, "users", "session_duration_seconds"),
+		"Longest active login session duration (seconds).", []string{
+			"type", "user", "address",
+		}, nil,
+	)
+)
+
+type userSessionKey struct {
+	t       string
+	user    string
+	address string
+}
+
+func (c *collector) collectUsersMetrics(ch chan<- prometheus.Metric) {
+	defer c.RUnlock()
+	c.RLock()
+
+	if c.usersStat == nil {
+		return
+	}
+
+	for t, sessions := range c.usersStat.Sessions {
+		ch <- prometheus.MustNewConstMetric(
+			usersSessionsDesc,
+			prometheus.GaugeValue,
+			float64(len(sessions)),
+			t,
+		)
+
+		// The same user may have several sessions from the same address
+		durations := make(map[userSessionKey]uint64, len(sessions))
+		for _, s := range sessions {
+			k := userSessionKey{s.Type, s.User, s.Address}
+			if d, ok := durations[k]; !ok || s.Duration > d {
+				durations[k] = s.Duration
+			}
+		}
+
+		for k, d := range durations {
+			ch <- prometheus.MustNewConstMetric(
+				usersSessionInfoDesc,
+				prometheus.GaugeValue,
+				float64(1),
+				k.t, k.user, k.address,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				usersSessionDurationDesc,
+				prometheus.GaugeValue,
+				float64(d),
+				k.t, k.user, k.address,
+			)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package config reads the exporter configuration file, as in:
+//
+//	modules:
+//	  default:
+//	    user: ubnt
+//	    password_file: /etc/edgemax-exporter/password
+//	    tls:
+//	      ca_file: /etc/edgemax-exporter/ca.pem
+//	    topics: [interfaces, system-stats]
+//	targets:
+//	  - host: https://192.168.1.1
+//	  - host: https://10.0.0.1
+//	    module: default
+package config
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultModule is used by targets that do not name a module.
+const DefaultModule = "default"
+
+type TLSConfig struct {
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+	CAFile             string `yaml:"ca_file"`
+}
+
+// Module is a named set of credentials and settings shared by targets.
+type Module struct {
+	User         string    `yaml:"user"`
+	Password     string    `yaml:"password"`
+	PasswordFile string    `yaml:"password_file"`
+	TLS          TLSConfig `yaml:"tls"`
+
+	// Topics to subscribe to. All known topics when empty.
+	Topics []string `yaml:"topics"`
+}
+
+// Target is an EdgeMAX router to export metrics from.
+type Target struct {
+	Host   string `yaml:"host"`
+	Module string `yaml:"module"`
+}
+
+type Config struct {
+	Modules map[string]Module `yaml:"modules"`
+	Targets []Target          `yaml:"targets"`
+}
+
+func (m *Module) readPasswordFile() error {
+	if m.PasswordFile == "" {
+		return nil
+	}
+
+	if m.Password != "" {
+		return fmt.Errorf("password and password_file are mutually exclusive")
+	}
+
+	r, err := ioutil.ReadFile(m.PasswordFile)
+	if err != nil {
+		return err
+	}
+
+	m.Password = strings.TrimRight(string(r), "\r\n")
+	return nil
+}
+
+// LoadCAFile reads a single PEM-encoded CA certificate.
+// It returns a nil pool when path is empty.
+func LoadCAFile(path string) (*x509.CertPool, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	r, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(r)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM certificate found", path)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return pool, nil
+}
+
+func (c *Config) init() error {
+	if c.Modules == nil {
+		c.Modules = make(map[string]Module)
+	}
+
+	for name, m := range c.Modules {
+		if err := m.readPasswordFile(); err != nil {
+			return fmt.Errorf("module %s: %s", name, err)
+		}
+
+		if !m.TLS.InsecureSkipVerify {
+			if _, err := LoadCAFile(m.TLS.CAFile); err != nil {
+				return fmt.Errorf("module %s: %s", name, err)
+			}
+		}
+
+		c.Modules[name] = m
+	}
+
+	seen := make(map[string]bool, len(c.Targets))
+
+	for i, t := range c.Targets {
+		if t.Host == "" {
+			return fmt.Errorf("target without host")
+		}
+
+		if t.Module == "" {
+			c.Targets[i].Module = DefaultModule
+		}
+
+		// The default module may be provided by the caller
+		if _, ok := c.Modules[t.Module]; !ok && t.Module != "" && t.Module != DefaultModule {
+			return fmt.Errorf("target %s: unknown module %s", t.Host, t.Module)
+		}
+
+		host := NormalizeHost(t.Host)
+		if seen[host] {
+			return fmt.Errorf("duplicate target: %s", t.Host)
+		}
+
+		seen[host] = true
+	}
+
+	return nil
+}
+
+// NormalizeHost defaults to HTTPS when the host has no scheme, as in
+// "192.168.1.1".
+func NormalizeHost(host string) string {
+	if strings.Contains(host, "://") {
+		return host
+	}
+
+	return "https://" + host
+}
+
+// Load reads a YAML configuration file.
+func Load(path string) (*Config, error) {
+	r, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Config{}
+	if err := yaml.UnmarshalStrict(r, c); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	if err := c.init(); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return c, nil
+}
@@ -5,4 +5,5 @@ go 1.14
 require (
 	github.com/gorilla/websocket v1.4.2
 	github.com/prometheus/client_golang v1.6.0
+	gopkg.in/yaml.v2 v2.4.0
 )